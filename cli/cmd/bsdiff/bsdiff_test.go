@@ -0,0 +1,133 @@
+package bsdiff
+
+import (
+	"bytes"
+	cryptorand "crypto/rand"
+	"math/rand"
+	"testing"
+)
+
+func roundTrip(t *testing.T, old, newBuf []byte) {
+	t.Helper()
+	patch, err := Diff(old, newBuf)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	got, err := Patch(old, patch)
+	if err != nil {
+		t.Fatalf("Patch failed: %v", err)
+	}
+	if !bytes.Equal(got, newBuf) {
+		t.Fatalf("round trip mismatch: got %d bytes, want %d bytes", len(got), len(newBuf))
+	}
+}
+
+func TestRoundTrip_SmallEdit(t *testing.T) {
+	old := []byte("the quick brown fox jumps over the lazy dog")
+	newBuf := []byte("the quick brown fox leaps over the lazy dog")
+	roundTrip(t, old, newBuf)
+}
+
+func TestRoundTrip_Identical(t *testing.T) {
+	data := []byte("nothing changed here at all")
+	roundTrip(t, data, data)
+}
+
+func TestRoundTrip_EmptyOld(t *testing.T) {
+	roundTrip(t, nil, []byte("brand new content with no old file to diff against"))
+}
+
+func TestRoundTrip_EmptyNew(t *testing.T) {
+	roundTrip(t, []byte("some old content"), nil)
+}
+
+func TestRoundTrip_BothEmpty(t *testing.T) {
+	roundTrip(t, nil, nil)
+}
+
+func TestRoundTrip_CompletelyDifferent(t *testing.T) {
+	old := bytes.Repeat([]byte{0xAA}, 256)
+	newBuf := bytes.Repeat([]byte{0x55}, 256)
+	roundTrip(t, old, newBuf)
+}
+
+func TestRoundTrip_Random(t *testing.T) {
+	for trial := 0; trial < 20; trial++ {
+		old := randomBytes(rand.Intn(2000))
+		newBuf := mutate(old)
+		roundTrip(t, old, newBuf)
+	}
+}
+
+func TestRoundTrip_RearrangedChunks(t *testing.T) {
+	a := bytes.Repeat([]byte("AAAA"), 50)
+	b := bytes.Repeat([]byte("BBBB"), 50)
+	c := bytes.Repeat([]byte("CCCC"), 50)
+	old := append(append(append([]byte{}, a...), b...), c...)
+	newBuf := append(append(append([]byte{}, c...), a...), b...)
+	roundTrip(t, old, newBuf)
+}
+
+func TestPatch_RejectsBadMagic(t *testing.T) {
+	if _, err := Patch([]byte("old"), []byte("not a real patch")); err == nil {
+		t.Fatal("expected Patch to reject a patch with an invalid magic header")
+	}
+}
+
+func TestPatch_RejectsTruncatedPatch(t *testing.T) {
+	old := []byte("the quick brown fox jumps over the lazy dog")
+	newBuf := []byte("the quick brown fox leaps over the lazy dog")
+	patch, err := Diff(old, newBuf)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if _, err := Patch(old, patch[:len(patch)-5]); err == nil {
+		t.Fatal("expected Patch to reject a truncated patch")
+	}
+}
+
+func TestPatch_RejectsWrongOldFile(t *testing.T) {
+	old := []byte("the quick brown fox jumps over the lazy dog, repeated so matches are long enough to encode as copies")
+	newBuf := []byte("the quick brown fox leaps over the lazy dog, repeated so matches are long enough to encode as copies")
+	patch, err := Diff(old, newBuf)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	wrongOld := randomBytes(len(old))
+	got, err := Patch(wrongOld, patch)
+	if err == nil && bytes.Equal(got, newBuf) {
+		t.Fatal("expected patching against the wrong old file to fail or produce different output")
+	}
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	cryptorand.Read(b)
+	return b
+}
+
+// mutate returns a copy of old with a few random edits, so Diff has a
+// realistic mix of matching and non-matching regions to encode.
+func mutate(old []byte) []byte {
+	out := append([]byte{}, old...)
+	edits := rand.Intn(5) + 1
+	for i := 0; i < edits; i++ {
+		switch rand.Intn(3) {
+		case 0: // flip a byte
+			if len(out) > 0 {
+				out[rand.Intn(len(out))] = byte(rand.Intn(256))
+			}
+		case 1: // insert
+			pos := rand.Intn(len(out) + 1)
+			out = append(out[:pos], append(randomBytes(rand.Intn(20)+1), out[pos:]...)...)
+		case 2: // delete
+			if len(out) > 0 {
+				pos := rand.Intn(len(out))
+				n := rand.Intn(len(out)-pos) + 1
+				out = append(out[:pos], out[pos+n:]...)
+			}
+		}
+	}
+	return out
+}
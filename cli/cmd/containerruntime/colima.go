@@ -0,0 +1,22 @@
+package containerruntime
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// colimaRuntime wraps the docker CLI pointed at Colima's Docker context.
+// Colima runs its own Linux VM and container engine but leaves a regular
+// docker-compatible CLI surface once that VM is up, so it reuses cliRuntime
+// for everything except availability, which additionally requires the
+// colima CLI itself and a running VM.
+type colimaRuntime struct {
+	cliRuntime
+}
+
+func (r *colimaRuntime) Available() error {
+	if err := exec.Command("colima", "status").Run(); err != nil {
+		return fmt.Errorf("colima is not running. Start it with 'colima start' and try again")
+	}
+	return r.cliRuntime.Available()
+}
@@ -0,0 +1,334 @@
+// Package containerruntime abstracts the container engine used to run
+// LlamaFarm's service containers (server, designer, runtime) so the cmd
+// package isn't hard-wired to the docker CLI. Docker, Podman, nerdctl and
+// Colima all expose a docker-compatible CLI surface once running, so those
+// backends are a thin exec.Command wrapper with engine-specific quirks
+// isolated behind the Runtime interface; the WSL backend instead proxies
+// every invocation through wsl.exe to run docker inside a managed distro.
+package containerruntime
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// EnvVar selects the runtime explicitly; if unset or "auto", Detect probes PATH.
+const EnvVar = "LF_CONTAINER_RUNTIME"
+
+// PortMapping binds a container port to a specific host port.
+type PortMapping struct {
+	Host      int
+	Container int
+	Protocol  string
+}
+
+// PortPolicy controls whether Run prefers a fixed host port or falls back to
+// engine-assigned dynamic ports.
+type PortPolicy struct {
+	PreferredHostPort int
+	Forced            bool
+}
+
+// RunSpec describes a container to start.
+type RunSpec struct {
+	Name           string
+	Image          string
+	DynamicPublish bool
+	StaticPorts    []PortMapping
+	Env            map[string]string
+	Volumes        []string
+	AddHosts       []string
+	Labels         map[string]string
+	Workdir        string
+	Entrypoint     []string
+	Cmd            []string
+}
+
+// Runtime is implemented by each supported container engine.
+type Runtime interface {
+	// Name identifies the backend, e.g. "docker", "podman", "nerdctl".
+	Name() string
+	// Available reports whether the engine's CLI is usable on this host.
+	Available() error
+	Pull(image string) error
+	Exists(name string) bool
+	Running(name string) bool
+	Remove(name string) error
+	// Run starts name if it isn't already running and returns the resolved
+	// container-port -> host-port mapping.
+	Run(spec RunSpec, policy *PortPolicy) (map[int]int, error)
+	// PublishedPorts returns a map like "80/tcp" -> "49154".
+	PublishedPorts(name string) (map[string]string, error)
+}
+
+// cliRuntime implements Runtime on top of a docker-compatible CLI binary.
+// execCommand builds the *exec.Cmd used to invoke binary; it defaults to
+// exec.Command, but backends that don't run the CLI directly on the host
+// (e.g. WSL, which must shell out through wsl.exe) override it.
+type cliRuntime struct {
+	name        string
+	binary      string
+	execCommand func(name string, args ...string) *exec.Cmd
+}
+
+func (r *cliRuntime) cmd(args ...string) *exec.Cmd {
+	execCommand := r.execCommand
+	if execCommand == nil {
+		execCommand = exec.Command
+	}
+	return execCommand(r.binary, args...)
+}
+
+func (r *cliRuntime) Name() string { return r.name }
+
+func (r *cliRuntime) Available() error {
+	if err := r.cmd("--version").Run(); err != nil {
+		return fmt.Errorf("%s is not available. Please install %s and try again", r.name, r.binary)
+	}
+	return nil
+}
+
+func (r *cliRuntime) Pull(image string) error {
+	out, err := r.cmd("pull", image).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s pull failed: %v\n%s", r.name, err, string(out))
+	}
+	return nil
+}
+
+func (r *cliRuntime) Exists(name string) bool {
+	return r.psNameMatches(name, true)
+}
+
+func (r *cliRuntime) Running(name string) bool {
+	return r.psNameMatches(name, false)
+}
+
+func (r *cliRuntime) psNameMatches(name string, includeStopped bool) bool {
+	args := []string{"ps", "--format", "{{.Names}}"}
+	if includeStopped {
+		args = append(args, "-a")
+	}
+	out, err := r.cmd(args...).Output()
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *cliRuntime) Remove(name string) error {
+	if !r.Exists(name) {
+		return nil
+	}
+	out, err := r.cmd("rm", "-f", name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s rm failed: %v\n%s", r.name, err, string(out))
+	}
+	return nil
+}
+
+func (r *cliRuntime) Run(spec RunSpec, policy *PortPolicy) (map[int]int, error) {
+	if err := r.Available(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(spec.Name) == "" || strings.TrimSpace(spec.Image) == "" {
+		return nil, errors.New("container name and image are required")
+	}
+
+	if r.Exists(spec.Name) && !r.Running(spec.Name) {
+		if err := r.Remove(spec.Name); err != nil {
+			return nil, fmt.Errorf("failed to remove existing container %s: %w", spec.Name, err)
+		}
+	}
+
+	if r.Running(spec.Name) {
+		return r.resolvePublishedPorts(spec.Name)
+	}
+
+	_ = r.Pull(spec.Image)
+
+	args := []string{"run", "-d", "--name", spec.Name}
+
+	useDynamic := false
+	if policy != nil && policy.PreferredHostPort > 0 && len(spec.StaticPorts) > 0 {
+		if isHostPortAvailable(policy.PreferredHostPort) {
+			for _, pm := range spec.StaticPorts {
+				hostPort := policy.PreferredHostPort
+				if pm.Host > 0 {
+					hostPort = pm.Host
+				}
+				protocol := pm.Protocol
+				if protocol == "" {
+					protocol = "tcp"
+				}
+				args = append(args, "-p", fmt.Sprintf("%d:%d/%s", hostPort, pm.Container, protocol))
+			}
+		} else if policy.Forced {
+			return nil, fmt.Errorf("port %d is already in use", policy.PreferredHostPort)
+		} else {
+			useDynamic = true
+		}
+	} else {
+		useDynamic = true
+	}
+
+	if useDynamic {
+		args = append(args, "-P")
+	}
+	for k, v := range spec.Env {
+		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
+	}
+	for _, v := range spec.Volumes {
+		args = append(args, "-v", v)
+	}
+	for _, h := range spec.AddHosts {
+		args = append(args, "--add-host", h)
+	}
+	for k, v := range spec.Labels {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", k, v))
+	}
+	if strings.TrimSpace(spec.Workdir) != "" {
+		args = append(args, "-w", spec.Workdir)
+	}
+	if len(spec.Entrypoint) > 0 {
+		args = append(args, "--entrypoint", strings.Join(spec.Entrypoint, " "))
+	}
+
+	args = append(args, spec.Image)
+	args = append(args, spec.Cmd...)
+
+	if out, err := r.cmd(args...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to start %s container: %v\n%s", r.name, err, string(out))
+	}
+
+	return r.resolvePublishedPorts(spec.Name)
+}
+
+func (r *cliRuntime) resolvePublishedPorts(name string) (map[int]int, error) {
+	published, err := r.PublishedPorts(name)
+	if err != nil {
+		return nil, err
+	}
+	resolved := make(map[int]int)
+	for key, val := range published {
+		parts := strings.Split(key, "/")
+		if len(parts) == 0 {
+			continue
+		}
+		cp, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+		hp, err := strconv.Atoi(val)
+		if err != nil {
+			continue
+		}
+		resolved[cp] = hp
+	}
+	return resolved, nil
+}
+
+func (r *cliRuntime) PublishedPorts(name string) (map[string]string, error) {
+	out, err := r.cmd("port", name).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%s port failed: %v\n%s", r.binary, err, string(out))
+	}
+	res := make(map[string]string)
+	s := bufio.NewScanner(strings.NewReader(string(out)))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		parts := strings.Split(line, " -> ")
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		host := strings.TrimSpace(parts[1])
+		idx := strings.LastIndex(host, ":")
+		if idx > -1 && idx+1 < len(host) {
+			res[key] = host[idx+1:]
+		}
+	}
+	return res, nil
+}
+
+func isHostPortAvailable(port int) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	_ = l.Close()
+	return true
+}
+
+// Docker returns the Docker backend.
+func Docker() Runtime { return &cliRuntime{name: "docker", binary: "docker"} }
+
+// Podman returns the Podman backend. Podman's CLI is docker-compatible for
+// the subset of commands used here, including rootless mode.
+func Podman() Runtime { return &cliRuntime{name: "podman", binary: "podman"} }
+
+// Nerdctl returns the nerdctl (containerd) backend.
+func Nerdctl() Runtime { return &cliRuntime{name: "nerdctl", binary: "nerdctl"} }
+
+// Colima returns the Colima backend. Colima provides its own VM and
+// container runtime but still exposes a docker-compatible CLI once its VM
+// is started, so it reuses cliRuntime against the "docker" binary pointed
+// at colima's context; Available additionally requires the colima CLI and a
+// running colima VM.
+func Colima() Runtime { return &colimaRuntime{cliRuntime{name: "colima", binary: "docker"}} }
+
+// WSL returns the Windows WSL2 backend, which shells out to wsl.exe to run
+// docker inside a managed Linux distro rather than requiring Docker Desktop.
+func WSL() Runtime {
+	return &wslRuntime{cliRuntime{name: "wsl", binary: "docker", execCommand: wslExecCommand}}
+}
+
+// All lists the backends probed by Detect, in priority order.
+func All() []Runtime {
+	return []Runtime{Docker(), Podman(), Nerdctl(), Colima(), WSL()}
+}
+
+// ByName resolves a backend by its selector name ("docker", "podman",
+// "nerdctl", "colima", "wsl").
+func ByName(name string) (Runtime, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "docker":
+		return Docker(), nil
+	case "podman":
+		return Podman(), nil
+	case "nerdctl":
+		return Nerdctl(), nil
+	case "colima":
+		return Colima(), nil
+	case "wsl":
+		return WSL(), nil
+	default:
+		return nil, fmt.Errorf("unknown container runtime %q", name)
+	}
+}
+
+// Detect picks the runtime to use: an explicit non-"auto" EnvVar value wins,
+// otherwise the first available backend in All() wins.
+func Detect(selector string) (Runtime, error) {
+	selector = strings.ToLower(strings.TrimSpace(selector))
+	if selector != "" && selector != "auto" {
+		return ByName(selector)
+	}
+
+	for _, rt := range All() {
+		if rt.Available() == nil {
+			return rt, nil
+		}
+	}
+	return nil, errors.New("no container runtime found on PATH (tried docker, podman, nerdctl, colima, wsl)")
+}
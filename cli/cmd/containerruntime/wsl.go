@@ -0,0 +1,106 @@
+package containerruntime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// wslDistroEnvVar overrides which WSL distro hosts the docker daemon used by
+// the wsl backend.
+const wslDistroEnvVar = "LF_WSL_DISTRO"
+
+// defaultWSLDistro is used when wslDistroEnvVar isn't set.
+const defaultWSLDistro = "Ubuntu"
+
+// wslRuntime runs docker inside a managed WSL2 distro by shelling every
+// invocation out through wsl.exe, so `lf` works on Windows machines without
+// Docker Desktop. Because such a distro has no Docker-Desktop-managed
+// host.docker.internal DNS entry, Run resolves the Windows host's IP from
+// within the distro and substitutes it for any "host-gateway" add-host entry
+// before handing off to cliRuntime.
+type wslRuntime struct {
+	cliRuntime
+}
+
+func wslDistro() string {
+	if v := strings.TrimSpace(os.Getenv(wslDistroEnvVar)); v != "" {
+		return v
+	}
+	return defaultWSLDistro
+}
+
+// wslExecCommand runs name inside the configured WSL distro via wsl.exe,
+// used as cliRuntime's execCommand override for the wsl backend.
+func wslExecCommand(name string, args ...string) *exec.Cmd {
+	full := append([]string{"-d", wslDistro(), "--", name}, args...)
+	return exec.Command("wsl.exe", full...)
+}
+
+func (r *wslRuntime) Available() error {
+	if err := exec.Command("wsl.exe", "--status").Run(); err != nil {
+		return errors.New("wsl.exe is not available; WSL2 is required for the wsl container runtime backend")
+	}
+
+	distro := wslDistro()
+	out, err := exec.Command("wsl.exe", "-l", "-q").Output()
+	if err != nil || !listContainsDistro(out, distro) {
+		return fmt.Errorf("WSL distro %q is not installed; install it or set %s", distro, wslDistroEnvVar)
+	}
+
+	return r.cliRuntime.Available()
+}
+
+// listContainsDistro reports whether output (from `wsl.exe -l -q`, which
+// Windows emits as UTF-16LE) names distro. The null bytes interleaved by
+// UTF-16LE are stripped before comparing rather than fully decoding, since
+// distro names are themselves ASCII.
+func listContainsDistro(output []byte, distro string) bool {
+	clean := strings.ReplaceAll(string(output), "\x00", "")
+	for _, line := range strings.Split(clean, "\n") {
+		if strings.EqualFold(strings.TrimSpace(line), distro) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *wslRuntime) Run(spec RunSpec, policy *PortPolicy) (map[int]int, error) {
+	if hostIP, err := wslHostIP(); err == nil {
+		spec.AddHosts = rewriteHostGateway(spec.AddHosts, hostIP)
+	}
+	return r.cliRuntime.Run(spec, policy)
+}
+
+// wslHostIP returns the Windows host's IP as seen from inside the WSL
+// distro (its default route gateway), the WSL2 equivalent of
+// host.docker.internal when no Docker-Desktop integration provides that
+// DNS entry.
+func wslHostIP() (string, error) {
+	out, err := wslExecCommand("sh", "-c", "ip route show default | awk '{print $3}'").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine Windows host IP from WSL: %w", err)
+	}
+	ip := strings.TrimSpace(string(out))
+	if ip == "" {
+		return "", errors.New("could not determine Windows host IP from WSL default route")
+	}
+	return ip, nil
+}
+
+// rewriteHostGateway replaces the docker-engine "host-gateway" magic value
+// in addHosts entries with ip, since that value isn't recognized by every
+// docker build running inside a bare WSL distro.
+func rewriteHostGateway(addHosts []string, ip string) []string {
+	rewritten := make([]string, len(addHosts))
+	for i, h := range addHosts {
+		if name, ok := strings.CutSuffix(h, ":host-gateway"); ok {
+			rewritten[i] = fmt.Sprintf("%s:%s", name, ip)
+		} else {
+			rewritten[i] = h
+		}
+	}
+	return rewritten
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strconv"
 	"time"
+
+	"llamafarm-cli/internal/trace"
 )
 
 type DesignerLaunchOptions struct {
@@ -15,6 +17,7 @@ type DesignerLaunchOptions struct {
 func StartDesignerInBackground(ctx context.Context, opts DesignerLaunchOptions) (string, error) {
 	// Check orchestration mode - if native, designer is served by server
 	orchestrationMode := determineOrchestrationMode()
+	trace.Log("designer", "orchestration mode: %v, preferred port: %d", orchestrationMode, opts.PreferredPort)
 	if orchestrationMode == OrchestrationNative {
 		// Designer is served by server at root URL
 		serverURLToUse := serverURL
@@ -80,8 +83,8 @@ func StartDesignerInBackground(ctx context.Context, opts DesignerLaunchOptions)
 	readyCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
 	defer cancel()
 	check := HTTPGetReady(url)
-	if err := WaitForReadiness(readyCtx, check, 1*time.Second); err != nil {
-		logDebug(fmt.Sprintf("designer readiness wait timed out or failed: %v", err))
+	if err := WaitForReadiness(readyCtx, check, DefaultReadinessPolicy); err != nil {
+		trace.Log("designer", "readiness wait failed: %v", err)
 		// Return the URL anyway so the UI can still attempt to open it
 	}
 	return url, nil
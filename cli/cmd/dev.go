@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"llamafarm-cli/cmd/config"
+	"llamafarm-cli/internal/trace"
 
 	"github.com/spf13/cobra"
 )
@@ -16,6 +17,8 @@ var devCmd = &cobra.Command{
 	Short: "Developer mode: launch your project locally",
 	Long:  "Start an interactive chat session quickly for development and testing.",
 	Run: func(cmd *cobra.Command, args []string) {
+		printActiveTraceCategories()
+
 		if strings.TrimSpace(serverURL) == "" {
 			serverURL = "http://localhost:8000"
 		}
@@ -27,14 +30,17 @@ var devCmd = &cobra.Command{
 			fmt.Fprintf(os.Stderr, "No config file found in target directory. Run `lf init` to create a new project.\n")
 			os.Exit(1)
 		}
+		trace.Log("config", "loaded config from %s", cwd)
 
 		projectInfo, err := cfg.GetProjectInfo()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: Could not extract project info for watcher: %v\n", err)
+			trace.Log("watcher", "GetProjectInfo failed: %v", err)
 		} else {
 			// Start the config file watcher in background
 			if err := StartConfigWatcher(projectInfo.Namespace, projectInfo.Project); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: Failed to start config watcher: %v\n", err)
+				trace.Log("watcher", "StartConfigWatcher failed for %s/%s: %v", projectInfo.Namespace, projectInfo.Project, err)
 			}
 		}
 
@@ -53,3 +59,14 @@ func init() {
 		fmt.Fprintln(os.Stderr, "Hint: use --server-url to point to a specific server")
 	}
 }
+
+// printActiveTraceCategories prints a one-line startup banner naming the
+// LF_TRACE categories enabled for this run, so "lf dev" and "lf services
+// logs" surface that tracing is on instead of leaving it silently active.
+func printActiveTraceCategories() {
+	active := trace.Active()
+	if len(active) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Trace enabled: %s\n", strings.Join(active, ", "))
+}
@@ -1,18 +1,20 @@
 package cmd
 
 import (
-	"bufio"
 	"context"
 	"errors"
 	"fmt"
-	"net"
+	"io"
+	mathrand "math/rand"
 	"net/http"
 	"os"
-	"os/exec"
 	"regexp"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"llamafarm-cli/cmd/containerruntime"
+	"llamafarm-cli/internal/trace"
 )
 
 // versionPattern matches semantic versions with or without leading "v"
@@ -28,53 +30,63 @@ var knownComponents = map[string]bool{
 	"models":   true,
 }
 
-// ensureDockerAvailable checks whether docker is available on PATH
+// activeRuntime caches the container runtime backend selected via
+// LF_CONTAINER_RUNTIME (or auto-detected) so it's only resolved once per run.
+var (
+	activeRuntime     containerruntime.Runtime
+	activeRuntimeOnce sync.Once
+	activeRuntimeErr  error
+)
+
+// getContainerRuntime returns the selected container runtime backend,
+// resolving it on first use from LF_CONTAINER_RUNTIME (default: auto-detect
+// the first available of docker/podman/nerdctl/colima/wsl).
+func getContainerRuntime() (containerruntime.Runtime, error) {
+	activeRuntimeOnce.Do(func() {
+		activeRuntime, activeRuntimeErr = containerruntime.Detect(os.Getenv("LF_CONTAINER_RUNTIME"))
+	})
+	return activeRuntime, activeRuntimeErr
+}
+
+// ensureDockerAvailable checks whether the selected container runtime is
+// available on PATH. The name is kept for source compatibility; it now
+// checks whichever backend LF_CONTAINER_RUNTIME selects, not just docker.
 func ensureDockerAvailable() error {
-	if err := exec.Command("docker", "--version").Run(); err != nil {
-		return errors.New("docker is not available. Please install Docker and try again")
+	rt, err := getContainerRuntime()
+	if err != nil {
+		return err
 	}
-	return nil
+	return rt.Available()
 }
 
-// pullImage pulls a docker image, capturing output to avoid breaking TUIs
+// pullImage pulls an image via the selected container runtime, capturing
+// output to avoid breaking TUIs.
 func pullImage(image string) error {
-	pullCmd := exec.Command("docker", "pull", image)
-	out, err := pullCmd.CombinedOutput()
+	rt, err := getContainerRuntime()
 	if err != nil {
-		return fmt.Errorf("docker pull failed: %v\n%s", err, string(out))
+		return err
 	}
-	if debug && len(out) > 0 {
-		logDebug(fmt.Sprintf("docker pull output: %s", string(out)))
+	if err := rt.Pull(image); err != nil {
+		return err
 	}
+	trace.Log("docker", "%s pull %s complete", rt.Name(), image)
 	return nil
 }
 
 func containerExists(name string) bool {
-	cmd := exec.Command("docker", "ps", "-a", "--format", "{{.Names}}")
-	out, err := cmd.Output()
+	rt, err := getContainerRuntime()
 	if err != nil {
 		return false
 	}
-	for _, line := range strings.Split(string(out), "\n") {
-		if strings.TrimSpace(line) == name {
-			return true
-		}
-	}
-	return false
+	return rt.Exists(name)
 }
 
 func isContainerRunning(name string) bool {
-	cmd := exec.Command("docker", "ps", "--format", "{{.Names}}")
-	out, err := cmd.Output()
+	rt, err := getContainerRuntime()
 	if err != nil {
 		return false
 	}
-	for _, line := range strings.Split(string(out), "\n") {
-		if strings.TrimSpace(line) == name {
-			return true
-		}
-	}
-	return false
+	return rt.Running(name)
 }
 
 // resolveImageTag determines the appropriate Docker image tag based on version and environment variables
@@ -123,7 +135,9 @@ func getImageURL(component string) (string, error) {
 
 	baseURL := "ghcr.io/llama-farm/llamafarm"
 	tag := resolveImageTag(component, "latest")
-	return fmt.Sprintf("%s/%s:%s", baseURL, component, tag), nil
+	image := fmt.Sprintf("%s/%s:%s", baseURL, component, tag)
+	trace.Log("docker", "resolved image URL for %s: %s", component, image)
+	return image, nil
 }
 
 // getKnownComponentsList returns a comma-separated list of known components
@@ -168,194 +182,185 @@ type PortResolutionPolicy struct {
 }
 
 func removeContainer(name string) error {
-	if !containerExists(name) {
-		return nil
-	}
-	rmCmd := exec.Command("docker", "rm", "-f", name)
-	out, err := rmCmd.CombinedOutput()
+	rt, err := getContainerRuntime()
 	if err != nil {
-		return fmt.Errorf("docker rm failed: %v\n%s", err, string(out))
+		return err
 	}
-	if debug && len(out) > 0 {
-		logDebug(fmt.Sprintf("docker rm output: %s", string(out)))
+	if err := rt.Remove(name); err != nil {
+		return err
 	}
+	trace.Log("docker", "%s rm %s complete", rt.Name(), name)
 	return nil
 }
 
-func isHostPortAvailable(port int) bool {
-	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
-	if err != nil {
-		return false
+// toRuntimeSpec converts a ContainerRunSpec into the backend-agnostic
+// containerruntime.RunSpec.
+func toRuntimeSpec(spec ContainerRunSpec) containerruntime.RunSpec {
+	ports := make([]containerruntime.PortMapping, 0, len(spec.StaticPorts))
+	for _, pm := range spec.StaticPorts {
+		ports = append(ports, containerruntime.PortMapping{Host: pm.Host, Container: pm.Container, Protocol: pm.Protocol})
+	}
+	return containerruntime.RunSpec{
+		Name:           spec.Name,
+		Image:          spec.Image,
+		DynamicPublish: spec.DynamicPublish,
+		StaticPorts:    ports,
+		Env:            spec.Env,
+		Volumes:        spec.Volumes,
+		AddHosts:       spec.AddHosts,
+		Labels:         spec.Labels,
+		Workdir:        spec.Workdir,
+		Entrypoint:     spec.Entrypoint,
+		Cmd:            spec.Cmd,
 	}
-	_ = l.Close()
-	return true
+}
+
+func toRuntimePolicy(policy *PortResolutionPolicy) *containerruntime.PortPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &containerruntime.PortPolicy{PreferredHostPort: policy.PreferredHostPort, Forced: policy.Forced}
 }
 
 // StartContainerDetachedWithPolicy starts a container with either static port mapping
 // or dynamic published ports based on availability and the provided policy.
-// Returns a map of containerPort->hostPort that were published.
+// Returns a map of containerPort->hostPort that were published. The container
+// engine used is whichever backend LF_CONTAINER_RUNTIME selects (default:
+// auto-detect docker, podman, nerdctl, colima, or wsl).
 func StartContainerDetachedWithPolicy(spec ContainerRunSpec, policy *PortResolutionPolicy) (map[int]int, error) {
-	if err := ensureDockerAvailable(); err != nil {
+	rt, err := getContainerRuntime()
+	if err != nil {
 		return nil, err
 	}
+
 	if strings.TrimSpace(spec.Name) == "" || strings.TrimSpace(spec.Image) == "" {
 		return nil, errors.New("container name and image are required")
 	}
-
-	// Remove stale container if exists and not running
-	if containerExists(spec.Name) && !isContainerRunning(spec.Name) {
+	if rt.Exists(spec.Name) && !rt.Running(spec.Name) {
 		fmt.Fprintln(os.Stderr, "Removing existing container to refresh image/args...")
-		if err := removeContainer(spec.Name); err != nil {
-			return nil, fmt.Errorf("failed to remove existing container %s: %w", spec.Name, err)
-		}
-	}
-
-	// If already running, do nothing and return current published ports
-	if isContainerRunning(spec.Name) {
-		ports, _ := GetPublishedPorts(spec.Name)
-		resolved := make(map[int]int)
-		for key, val := range ports {
-			// key like "80/tcp"; extract container port
-			parts := strings.Split(key, "/")
-			if len(parts) > 0 {
-				if cp, err := strconv.Atoi(parts[0]); err == nil {
-					if hp, err2 := strconv.Atoi(val); err2 == nil {
-						resolved[cp] = hp
-					}
-				}
-			}
-		}
-		return resolved, nil
-	}
-
-	// Pull image best-effort (captured)
-	_ = pullImage(spec.Image)
-
-	runArgs := []string{"run", "-d", "--name", spec.Name}
-
-	useDynamic := false
-	if policy != nil && policy.PreferredHostPort > 0 && len(spec.StaticPorts) > 0 {
-		if isHostPortAvailable(policy.PreferredHostPort) {
-			for _, pm := range spec.StaticPorts {
-				hostPort := policy.PreferredHostPort
-				if pm.Host > 0 {
-					hostPort = pm.Host
-				}
-				protocol := pm.Protocol
-				if protocol == "" {
-					protocol = "tcp"
-				}
-				runArgs = append(runArgs, "-p", fmt.Sprintf("%d:%d/%s", hostPort, pm.Container, protocol))
-			}
-		} else {
-			if policy.Forced {
-				return nil, fmt.Errorf("port %d is already in use", policy.PreferredHostPort)
-			}
-			useDynamic = true
-		}
-	} else {
-		useDynamic = true
-	}
-
-	if useDynamic {
-		runArgs = append(runArgs, "-P")
-	}
-
-	for k, v := range spec.Env {
-		runArgs = append(runArgs, "-e", fmt.Sprintf("%s=%s", k, v))
-	}
-	for _, v := range spec.Volumes {
-		runArgs = append(runArgs, "-v", v)
-	}
-	for _, h := range spec.AddHosts {
-		runArgs = append(runArgs, "--add-host", h)
-	}
-	for k, v := range spec.Labels {
-		runArgs = append(runArgs, "--label", fmt.Sprintf("%s=%s", k, v))
-	}
-	if strings.TrimSpace(spec.Workdir) != "" {
-		runArgs = append(runArgs, "-w", spec.Workdir)
 	}
-	if len(spec.Entrypoint) > 0 {
-		runArgs = append(runArgs, "--entrypoint", strings.Join(spec.Entrypoint, " "))
-	}
-
-	runArgs = append(runArgs, spec.Image)
-	runArgs = append(runArgs, spec.Cmd...)
 
-	runCmd := exec.Command("docker", runArgs...)
-	runOut, err := runCmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("failed to start docker container: %v\n%s", err, string(runOut))
-	}
-	if debug && len(runOut) > 0 {
-		logDebug(fmt.Sprintf("docker run output: %s", string(runOut)))
-	}
-
-	// Resolve published ports
-	published, err := GetPublishedPorts(spec.Name)
+	resolved, err := rt.Run(toRuntimeSpec(spec), toRuntimePolicy(policy))
 	if err != nil {
 		return nil, err
 	}
-	resolved := make(map[int]int)
-	for key, val := range published {
-		parts := strings.Split(key, "/")
-		if len(parts) > 0 {
-			if cp, err := strconv.Atoi(parts[0]); err == nil {
-				if hp, err2 := strconv.Atoi(val); err2 == nil {
-					resolved[cp] = hp
-				}
-			}
-		}
-	}
+	trace.Log("docker", "%s run %s resolved ports: %v", rt.Name(), spec.Name, resolved)
 	return resolved, nil
 }
 
-// GetPublishedPorts returns a map like "80/tcp" -> "49154"
+// GetPublishedPorts returns a map like "80/tcp" -> "49154" from whichever
+// container runtime backend is selected.
 func GetPublishedPorts(name string) (map[string]string, error) {
-	cmd := exec.Command("docker", "port", name)
-	out, err := cmd.CombinedOutput()
+	rt, err := getContainerRuntime()
 	if err != nil {
-		return nil, fmt.Errorf("docker port failed: %v\n%s", err, string(out))
-	}
-	res := make(map[string]string)
-	s := bufio.NewScanner(strings.NewReader(string(out)))
-	for s.Scan() {
-		line := strings.TrimSpace(s.Text())
-		// Example: "80/tcp -> 0.0.0.0:49154" or "80/tcp -> :::49154"
-		parts := strings.Split(line, " -> ")
-		if len(parts) != 2 {
-			continue
-		}
-		key := strings.TrimSpace(parts[0])
-		host := strings.TrimSpace(parts[1])
-		idx := strings.LastIndex(host, ":")
-		if idx > -1 && idx+1 < len(host) {
-			res[key] = host[idx+1:]
-		}
+		return nil, err
 	}
-	if debug && len(out) > 0 {
-		logDebug(fmt.Sprintf("docker port output: %s", string(out)))
+	res, err := rt.PublishedPorts(name)
+	if err != nil {
+		return nil, err
 	}
+	trace.Log("docker", "%s port %s: %v", rt.Name(), name, res)
 	return res, nil
 }
 
-func WaitForReadiness(ctx context.Context, check func() error, interval time.Duration) error {
-	t := time.NewTicker(interval)
-	defer t.Stop()
-	for {
+// ReadinessPolicy controls the backoff used between readiness checks and
+// when to give up early because the target looks permanently unreachable
+// rather than merely slow to start.
+type ReadinessPolicy struct {
+	InitialInterval        time.Duration
+	MaxInterval            time.Duration
+	Multiplier             float64
+	Jitter                 bool
+	MaxConsecutiveFailures int // 0 disables the short-circuit
+}
+
+// DefaultReadinessPolicy backs off from 500ms up to 5s, doubling each
+// attempt, and gives up early after 10 straight connection refusals (the
+// container almost certainly exited).
+var DefaultReadinessPolicy = ReadinessPolicy{
+	InitialInterval:        500 * time.Millisecond,
+	MaxInterval:            5 * time.Second,
+	Multiplier:             2,
+	Jitter:                 true,
+	MaxConsecutiveFailures: 10,
+}
+
+// WaitForReadiness polls check() until it succeeds, the context is done, or
+// policy.MaxConsecutiveFailures connection-refused errors occur in a row
+// (which indicates the container exited rather than being merely slow).
+// Unlike a fixed-interval poll, the wait between attempts grows from
+// policy.InitialInterval up to policy.MaxInterval, with optional jitter to
+// avoid synchronized retries against the same host. On timeout the last
+// check() error is wrapped into the returned error so callers see *why*
+// startup failed instead of a bare context deadline.
+func WaitForReadiness(ctx context.Context, check func() error, policy ReadinessPolicy) error {
+	start := time.Now()
+	if policy.InitialInterval <= 0 {
+		policy.InitialInterval = DefaultReadinessPolicy.InitialInterval
+	}
+	if policy.MaxInterval <= 0 {
+		policy.MaxInterval = DefaultReadinessPolicy.MaxInterval
+	}
+	if policy.Multiplier <= 1 {
+		policy.Multiplier = DefaultReadinessPolicy.Multiplier
+	}
+
+	var lastErr error
+	interval := policy.InitialInterval
+	consecutiveFailures := 0
+
+	for attempt := 0; ; attempt++ {
+		if err := check(); err == nil {
+			trace.Log("ready", "readiness check succeeded after %d attempt(s), %s", attempt+1, time.Since(start))
+			return nil
+		} else {
+			lastErr = err
+			trace.Log("ready", "readiness check attempt %d failed: %v", attempt+1, err)
+			if isConnectionRefused(err) {
+				consecutiveFailures++
+				if policy.MaxConsecutiveFailures > 0 && consecutiveFailures >= policy.MaxConsecutiveFailures {
+					return fmt.Errorf("readiness check failed %d times in a row (connection refused), giving up: %w", consecutiveFailures, lastErr)
+				}
+			} else {
+				consecutiveFailures = 0
+			}
+		}
+
+		wait := interval
+		if policy.Jitter {
+			wait += time.Duration(mathrand.Int63n(int64(wait/2) + 1))
+		}
+
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		case <-t.C:
-			if err := check(); err == nil {
-				return nil
-			}
+			return fmt.Errorf("readiness timed out after %s: last error: %w", time.Since(start), lastErr)
+		case <-time.After(wait):
+		}
+
+		interval = time.Duration(float64(interval) * policy.Multiplier)
+		if interval > policy.MaxInterval {
+			interval = policy.MaxInterval
 		}
 	}
 }
 
+// isConnectionRefused reports whether err looks like the peer actively
+// refused the connection, as opposed to a timeout or a non-2xx response.
+func isConnectionRefused(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "connection refused")
+}
+
 func HTTPGetReady(url string) func() error {
+	return HTTPGetReadyWithExpect(url, nil, "")
+}
+
+// HTTPGetReadyWithExpect builds a readiness check that additionally requires
+// the response status to be in statusCodes (when non-empty; otherwise any
+// 2xx is accepted) and, when bodyContains is non-empty, that the response
+// body contains that substring. This lets callers require a specific
+// "/healthz" response (e.g. status 200 with body containing "ok") rather
+// than treating any 2xx as ready.
+func HTTPGetReadyWithExpect(url string, statusCodes []int, bodyContains string) func() error {
 	return func() error {
 		req, err := http.NewRequest(http.MethodGet, url, nil)
 		if err != nil {
@@ -367,9 +372,36 @@ func HTTPGetReady(url string) func() error {
 			return err
 		}
 		defer resp.Body.Close()
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+
+		if len(statusCodes) > 0 {
+			if !containsInt(statusCodes, resp.StatusCode) {
+				return fmt.Errorf("status %d not in expected %v", resp.StatusCode, statusCodes)
+			}
+		} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+
+		if bodyContains == "" {
+			io.Copy(io.Discard, resp.Body)
 			return nil
 		}
-		return fmt.Errorf("status %d", resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		if !strings.Contains(string(body), bodyContains) {
+			return fmt.Errorf("response body did not contain %q", bodyContains)
+		}
+		return nil
+	}
+}
+
+func containsInt(haystack []int, needle int) bool {
+	for _, v := range haystack {
+		if v == needle {
+			return true
+		}
 	}
+	return false
 }
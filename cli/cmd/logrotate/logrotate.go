@@ -0,0 +1,221 @@
+// Package logrotate rotates and prunes LlamaFarm service log files so a
+// long-lived dev server doesn't fill disk over time.
+package logrotate
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Env var overrides for DefaultPolicy's thresholds. These are the interim
+// way to tune rotation without a project config file: the project config
+// struct (llamafarm-cli/cmd/config) doesn't carry a `logging:` block yet to
+// persist this properly, so PolicyFromEnv is what the automatic rotation
+// before a service starts uses (`lf services logs rotate` builds its Policy
+// from flags instead); swap it for a config-driven Policy once that block
+// exists.
+const (
+	maxSizeEnvVar     = "LF_LOG_MAX_SIZE_BYTES"
+	maxAgeEnvVar      = "LF_LOG_MAX_AGE"
+	maxBackupsEnvVar  = "LF_LOG_MAX_BACKUPS"
+	compressBackupVar = "LF_LOG_COMPRESS"
+)
+
+// Policy controls when a log file is rotated and how many backups are kept.
+type Policy struct {
+	// MaxSizeBytes rotates the live log once it grows past this size. Zero
+	// disables the size check.
+	MaxSizeBytes int64
+	// MaxAge rotates the live log once it's older than this, regardless of
+	// size. Zero disables the age check.
+	MaxAge time.Duration
+	// MaxBackups is how many rotated backups to retain; older ones are
+	// deleted.
+	MaxBackups int
+	// Compress gzips each backup as it is rotated out, including the newest
+	// (<path>.1.gz).
+	Compress bool
+}
+
+// DefaultPolicy matches the thresholds documented for `lf services logs rotate`.
+var DefaultPolicy = Policy{
+	MaxSizeBytes: 100 * 1024 * 1024,
+	MaxAge:       7 * 24 * time.Hour,
+	MaxBackups:   5,
+}
+
+// PolicyFromEnv returns DefaultPolicy with any of its thresholds overridden
+// by LF_LOG_MAX_SIZE_BYTES, LF_LOG_MAX_AGE, LF_LOG_MAX_BACKUPS, and
+// LF_LOG_COMPRESS, so a deployment can tune rotation without recompiling.
+func PolicyFromEnv() Policy {
+	policy := DefaultPolicy
+
+	if v := strings.TrimSpace(os.Getenv(maxSizeEnvVar)); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			policy.MaxSizeBytes = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(maxAgeEnvVar)); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			policy.MaxAge = d
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(maxBackupsEnvVar)); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			policy.MaxBackups = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(compressBackupVar)); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			policy.Compress = b
+		}
+	}
+
+	return policy
+}
+
+// RotateIfNeeded checks logPath against policy and, if it's due for
+// rotation, shifts existing backups up (<path>.1 -> .2, ...), deletes
+// backups past MaxBackups, renames logPath to <path>.1 (gzipping it when
+// Compress is set), and recreates an empty logPath so the caller can keep
+// appending to the same path. It reports whether a rotation happened.
+func RotateIfNeeded(logPath string, policy Policy) (bool, error) {
+	info, err := os.Stat(logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to stat %s: %w", logPath, err)
+	}
+
+	due := policy.MaxSizeBytes > 0 && info.Size() >= policy.MaxSizeBytes
+	due = due || (policy.MaxAge > 0 && time.Since(info.ModTime()) >= policy.MaxAge)
+	if !due {
+		return false, nil
+	}
+
+	if err := shiftBackups(logPath, policy); err != nil {
+		return false, err
+	}
+
+	if err := os.Rename(logPath, logPath+".1"); err != nil {
+		return false, fmt.Errorf("failed to rotate %s: %w", logPath, err)
+	}
+
+	if policy.Compress {
+		if err := compressBackup(logPath + ".1"); err != nil {
+			return false, err
+		}
+	}
+
+	// Recreate the live file at the same path so a writer that reopens it
+	// (or a follower that detects the rotation) finds it immediately.
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return false, fmt.Errorf("failed to recreate %s after rotation: %w", logPath, err)
+	}
+	f.Close()
+
+	return true, nil
+}
+
+// backupFile is one rotated <path>.N or <path>.N.gz backup.
+type backupFile struct {
+	path       string
+	index      int
+	compressed bool
+}
+
+// shiftBackups renames each existing backup from index N to N+1, from the
+// oldest down to .1, and deletes anything that would land past MaxBackups.
+func shiftBackups(logPath string, policy Policy) error {
+	backups := existingBackups(logPath)
+
+	for _, b := range backups {
+		if policy.MaxBackups > 0 && b.index >= policy.MaxBackups {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove old backup %s: %w", b.path, err)
+			}
+			continue
+		}
+		newPath := fmt.Sprintf("%s.%d", logPath, b.index+1)
+		if b.compressed {
+			newPath += ".gz"
+		}
+		if err := os.Rename(b.path, newPath); err != nil {
+			return fmt.Errorf("failed to shift backup %s: %w", b.path, err)
+		}
+	}
+
+	return nil
+}
+
+// existingBackups returns <path>.N[.gz] backups sorted from oldest (highest
+// index) to newest, so shiftBackups can rename starting from the back
+// without clobbering a not-yet-moved file.
+func existingBackups(logPath string) []backupFile {
+	dir := filepath.Dir(logPath)
+	base := filepath.Base(logPath)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var backups []backupFile
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, base+".") {
+			continue
+		}
+		rest := strings.TrimPrefix(name, base+".")
+		compressed := strings.HasSuffix(rest, ".gz")
+		rest = strings.TrimSuffix(rest, ".gz")
+		idx, err := strconv.Atoi(rest)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, name), index: idx, compressed: compressed})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].index > backups[j].index })
+	return backups
+}
+
+// compressBackup gzips path in place, replacing it with path+".gz".
+func compressBackup(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s for compression: %w", path, err)
+	}
+	defer src.Close()
+
+	dstPath := path + ".gz"
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dstPath, err)
+	}
+	defer dst.Close()
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		return fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finalize %s: %w", dstPath, err)
+	}
+
+	src.Close()
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove uncompressed backup %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package logrotate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicyFromEnv_DefaultsWithoutOverrides(t *testing.T) {
+	got := PolicyFromEnv()
+	if got != DefaultPolicy {
+		t.Errorf("PolicyFromEnv() = %+v, want DefaultPolicy %+v", got, DefaultPolicy)
+	}
+}
+
+func TestPolicyFromEnv_Overrides(t *testing.T) {
+	t.Setenv(maxSizeEnvVar, "12345")
+	t.Setenv(maxAgeEnvVar, "48h")
+	t.Setenv(maxBackupsEnvVar, "2")
+	t.Setenv(compressBackupVar, "true")
+
+	got := PolicyFromEnv()
+	want := Policy{MaxSizeBytes: 12345, MaxAge: 48 * time.Hour, MaxBackups: 2, Compress: true}
+	if got != want {
+		t.Errorf("PolicyFromEnv() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPolicyFromEnv_IgnoresInvalidValues(t *testing.T) {
+	t.Setenv(maxSizeEnvVar, "not-a-number")
+	t.Setenv(maxAgeEnvVar, "not-a-duration")
+	t.Setenv(maxBackupsEnvVar, "-1")
+
+	got := PolicyFromEnv()
+	if got != DefaultPolicy {
+		t.Errorf("PolicyFromEnv() with invalid overrides = %+v, want DefaultPolicy %+v", got, DefaultPolicy)
+	}
+}
@@ -0,0 +1,88 @@
+// Package orchestrator describes the services LlamaFarm can run locally
+// (server, rag, universal-runtime, ...) and the hardware-specific Python
+// packages each one needs.
+package orchestrator
+
+// HardwareCapability identifies the accelerator available on the host, used
+// to pick the right wheel index for packages like torch and
+// llama-cpp-python.
+type HardwareCapability string
+
+const (
+	HardwareCPU   HardwareCapability = "cpu"
+	HardwareCUDA  HardwareCapability = "cuda"
+	HardwareMetal HardwareCapability = "metal"
+	HardwareROCm  HardwareCapability = "rocm"
+)
+
+// PackageSpec describes how to install a single Python package for a given
+// set of hardware capabilities.
+type PackageSpec struct {
+	Name string
+	// Version is a pip-style version constraint, e.g. ">=2.0.0".
+	Version string
+	// UseIndexURL means install with --index-url (the package isn't
+	// published to PyPI at all for this hardware); false means
+	// --extra-index-url (PyPI has a fallback build).
+	UseIndexURL bool
+	// FallbackToDefault allows falling back to the default PyPI index when
+	// no WheelURLs entry matches the requested hardware.
+	FallbackToDefault bool
+	// WheelURLs maps hardware capability to its wheel index URL. An empty
+	// string means "use the default PyPI index" for that hardware.
+	WheelURLs map[HardwareCapability]string
+}
+
+// PyTorchSpec installs torch from PyTorch's own wheel index for CPU and
+// ROCm; CUDA and Metal builds are published to PyPI directly.
+var PyTorchSpec = PackageSpec{
+	Name:              "torch",
+	Version:           ">=2.2.0",
+	UseIndexURL:       true,
+	FallbackToDefault: true,
+	WheelURLs: map[HardwareCapability]string{
+		HardwareCPU:   "https://download.pytorch.org/whl/cpu",
+		HardwareCUDA:  "",
+		HardwareMetal: "",
+		HardwareROCm:  "https://download.pytorch.org/whl/rocm6.4",
+	},
+}
+
+// LlamaCppSpec installs llama-cpp-python's prebuilt wheels as an
+// extra index, since PyPI's sdist requires a local compiler toolchain.
+var LlamaCppSpec = PackageSpec{
+	Name:              "llama-cpp-python",
+	Version:           ">=0.2.90",
+	UseIndexURL:       false,
+	FallbackToDefault: true,
+	WheelURLs: map[HardwareCapability]string{
+		HardwareCPU:   "https://abetlen.github.io/llama-cpp-python/whl/cpu",
+		HardwareCUDA:  "https://abetlen.github.io/llama-cpp-python/whl/cu121",
+		HardwareMetal: "https://abetlen.github.io/llama-cpp-python/whl/metal",
+		HardwareROCm:  "https://abetlen.github.io/llama-cpp-python/whl/rocm",
+	},
+}
+
+// Service describes one component of a LlamaFarm deployment.
+type Service struct {
+	// HardwarePackages lists the hardware-specific packages this service
+	// needs installed; empty for services with no native dependencies.
+	HardwarePackages []PackageSpec
+}
+
+// ServiceGraph enumerates the known LlamaFarm services.
+var ServiceGraph = map[string]Service{
+	"server":            {},
+	"rag":               {},
+	"universal-runtime": {HardwarePackages: []PackageSpec{PyTorchSpec, LlamaCppSpec}},
+}
+
+// GetComponentPackages returns the hardware-specific packages a component
+// needs, or nil if the component is unknown or has none.
+func GetComponentPackages(component string) []PackageSpec {
+	svc, ok := ServiceGraph[component]
+	if !ok {
+		return nil
+	}
+	return svc.HardwarePackages
+}
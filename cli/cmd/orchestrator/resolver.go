@@ -0,0 +1,275 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// lockFileName is the reproducibility manifest written next to a project's
+// config, mirroring how npm/pnpm/uv separate dependency resolution from
+// install.
+const lockFileName = "llamafarm.lock.json"
+
+// ResolveOptions controls how ResolveHardwarePackages picks and caches a
+// wheel index for a package.
+type ResolveOptions struct {
+	// ConfigDir is the directory containing the project config; the lock
+	// file is written/read alongside it.
+	ConfigDir string
+	// Refresh forces re-probing the wheel indexes even if a lock entry
+	// already exists for this component+hardware.
+	Refresh bool
+	// Client is used for HEAD probes against wheel indexes; defaults to a
+	// short-timeout http.Client when nil.
+	Client *http.Client
+}
+
+// ResolvedPackage is a single resolved, reproducible package install target.
+type ResolvedPackage struct {
+	Package     string    `json:"package"`
+	Version     string    `json:"version"`
+	IndexURL    string    `json:"index_url"`
+	SHA256      string    `json:"sha256"`
+	ResolvedAt  time.Time `json:"resolved_at"`
+	UseIndexURL bool      `json:"use_index_url"`
+}
+
+// lockFile is the on-disk shape of llamafarm.lock.json.
+type lockFile struct {
+	// Packages is keyed by "<component>/<package>/<hardware>" so multiple
+	// components and hardware targets can share one lock file.
+	Packages map[string]ResolvedPackage `json:"packages"`
+}
+
+// defaultPackageIndexURL is PyPI's own simple index, used when a
+// PackageSpec.WheelURLs entry is "" ("use the default PyPI index").
+const defaultPackageIndexURL = "https://pypi.org/simple"
+
+// timeNow returns the current time; overridden in tests.
+var timeNow = time.Now
+
+// hrefPattern extracts anchor hrefs from a PEP 503 simple-index HTML page.
+var hrefPattern = regexp.MustCompile(`href="([^"]+)"`)
+
+// hardwareFallbackOrder lists the hardware capabilities to try, in priority
+// order, when the requested one has no published wheel for a package
+// (e.g. falling back from the CUDA index to the CPU one). Each accelerator
+// has a single index here; there is no chain through CUDA versions.
+var hardwareFallbackOrder = map[HardwareCapability][]HardwareCapability{
+	HardwareCUDA:  {HardwareCUDA, HardwareCPU},
+	HardwareROCm:  {HardwareROCm, HardwareCPU},
+	HardwareMetal: {HardwareMetal, HardwareCPU},
+	HardwareCPU:   {HardwareCPU},
+}
+
+// ResolveHardwarePackages resolves the wheel index to use for each
+// hardware-specific package a component needs, probing candidate indexes
+// with a HEAD request and falling back through hardwareFallbackOrder when
+// the preferred one 404s. Results are recorded in llamafarm.lock.json next
+// to the config so subsequent runs on other machines reproduce the same
+// resolution instead of re-probing, unless opts.Refresh is set.
+//
+// No installer command in this tree calls this yet; it's the resolution
+// step a future `lf` install flow for non-containerized components would
+// call before running pip against the recorded IndexURL/SHA256.
+func ResolveHardwarePackages(component string, hw HardwareCapability, opts ResolveOptions) ([]ResolvedPackage, error) {
+	packages := GetComponentPackages(component)
+	if len(packages) == 0 {
+		return nil, nil
+	}
+
+	lock, lockPath, err := loadLockFile(opts.ConfigDir)
+	if err != nil {
+		return nil, err
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	resolved := make([]ResolvedPackage, 0, len(packages))
+	for _, pkg := range packages {
+		key := lockKey(component, pkg.Name, hw)
+
+		if !opts.Refresh {
+			if cached, ok := lock.Packages[key]; ok {
+				resolved = append(resolved, cached)
+				continue
+			}
+		}
+
+		rp, err := resolvePackage(client, pkg, hw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s for %s/%s: %w", pkg.Name, component, hw, err)
+		}
+		lock.Packages[key] = rp
+		resolved = append(resolved, rp)
+	}
+
+	if err := writeLockFile(lockPath, lock); err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// resolvePackage picks the first hardware in the fallback order (starting
+// with hw) whose wheel index responds successfully to a HEAD request,
+// falling back to the default PyPI index when FallbackToDefault allows it.
+func resolvePackage(client *http.Client, pkg PackageSpec, hw HardwareCapability) (ResolvedPackage, error) {
+	candidates := hardwareFallbackOrder[hw]
+	if len(candidates) == 0 {
+		candidates = []HardwareCapability{hw, HardwareCPU}
+	}
+
+	for _, candidate := range candidates {
+		indexURL, ok := pkg.WheelURLs[candidate]
+		if !ok {
+			continue
+		}
+		if indexURL == "" {
+			// Explicitly means "use the default PyPI index" for this hardware.
+			return newResolvedPackage(client, pkg, indexURL)
+		}
+		if probeIndexHasVersion(client, indexURL, pkg.Name, pkg.Version) {
+			return newResolvedPackage(client, pkg, indexURL)
+		}
+	}
+
+	if pkg.FallbackToDefault {
+		return newResolvedPackage(client, pkg, "")
+	}
+
+	return ResolvedPackage{}, fmt.Errorf("no wheel index available for hardware %s and no default fallback permitted", hw)
+}
+
+// newResolvedPackage fetches pkg's real artifact hash from the chosen
+// index's PEP 503 simple-index page and records it alongside the
+// resolution, so the lock file reproduces an install down to the exact
+// wheel rather than just the index that was consulted.
+func newResolvedPackage(client *http.Client, pkg PackageSpec, indexURL string) (ResolvedPackage, error) {
+	sum, err := fetchPackageHash(client, indexURL, pkg.Name, pkg.Version)
+	if err != nil {
+		return ResolvedPackage{}, err
+	}
+	return ResolvedPackage{
+		Package:     pkg.Name,
+		Version:     pkg.Version,
+		IndexURL:    indexURL,
+		SHA256:      sum,
+		ResolvedAt:  timeNow(),
+		UseIndexURL: pkg.UseIndexURL,
+	}, nil
+}
+
+// fetchPackageHash fetches name's PEP 503 simple-index page from indexURL
+// (or defaultPackageIndexURL if indexURL is "") and returns the sha256 hash
+// published in the "#sha256=..." fragment of the link matching version, the
+// same hash pip itself verifies an install against.
+func fetchPackageHash(client *http.Client, indexURL, name, version string) (string, error) {
+	base := indexURL
+	if base == "" {
+		base = defaultPackageIndexURL
+	}
+	if len(base) > 0 && base[len(base)-1] != '/' {
+		base += "/"
+	}
+	pageURL := base + name + "/"
+
+	resp, err := client.Get(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch package index %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("package index %s returned %s", pageURL, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read package index %s: %w", pageURL, err)
+	}
+
+	for _, match := range hrefPattern.FindAllStringSubmatch(string(body), -1) {
+		href := match[1]
+		if !strings.Contains(href, version) {
+			continue
+		}
+		if idx := strings.Index(href, "#sha256="); idx != -1 {
+			return href[idx+len("#sha256="):], nil
+		}
+	}
+
+	return "", fmt.Errorf("no sha256 hash published for %s==%s at %s", name, version, pageURL)
+}
+
+// probeIndexHasVersion issues a HEAD request against the package's page on
+// indexURL to check it's reachable and serving something for this package.
+// A non-2xx response (typically 404) means this wheel index doesn't carry a
+// build for the requested hardware, and the caller should fall back.
+func probeIndexHasVersion(client *http.Client, indexURL, name, version string) bool {
+	probeURL := indexURL
+	if len(probeURL) > 0 && probeURL[len(probeURL)-1] != '/' {
+		probeURL += "/"
+	}
+	probeURL += name + "/"
+
+	req, err := http.NewRequest(http.MethodHead, probeURL, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+func lockKey(component, pkgName string, hw HardwareCapability) string {
+	return fmt.Sprintf("%s/%s/%s", component, pkgName, hw)
+}
+
+func lockFilePath(configDir string) string {
+	return filepath.Join(configDir, lockFileName)
+}
+
+func loadLockFile(configDir string) (*lockFile, string, error) {
+	path := lockFilePath(configDir)
+	lock := &lockFile{Packages: map[string]ResolvedPackage{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lock, path, nil
+		}
+		return nil, path, fmt.Errorf("failed to read %s: %w", lockFileName, err)
+	}
+
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, path, fmt.Errorf("failed to parse %s: %w", lockFileName, err)
+	}
+	if lock.Packages == nil {
+		lock.Packages = map[string]ResolvedPackage{}
+	}
+	return lock, path, nil
+}
+
+func writeLockFile(path string, lock *lockFile) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", lockFileName, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", lockFileName, err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,97 @@
+package orchestrator
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeSimpleIndex serves a minimal PEP 503 simple-index page for a single
+// package version, with a sha256 hash fragment on its wheel link.
+func fakeSimpleIndex(t *testing.T, name, version, sha256 string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		fmt.Fprintf(w, `<!DOCTYPE html><html><body>
+<a href="%s-%s-py3-none-any.whl#sha256=%s">%s-%s-py3-none-any.whl</a>
+</body></html>`, name, version, sha256, name, version)
+	}))
+}
+
+func TestResolveHardwarePackages(t *testing.T) {
+	const wantSHA = "deadbeef"
+	idx := fakeSimpleIndex(t, "torch", PyTorchSpec.Version, wantSHA)
+	defer idx.Close()
+
+	spec := PyTorchSpec
+	spec.WheelURLs = map[HardwareCapability]string{HardwareCPU: idx.URL}
+	ServiceGraph["test-service"] = Service{HardwarePackages: []PackageSpec{spec}}
+	defer delete(ServiceGraph, "test-service")
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	originalTimeNow := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = originalTimeNow }()
+
+	dir := t.TempDir()
+	resolved, err := ResolveHardwarePackages("test-service", HardwareCPU, ResolveOptions{ConfigDir: dir})
+	if err != nil {
+		t.Fatalf("ResolveHardwarePackages returned error: %v", err)
+	}
+	if len(resolved) != 1 {
+		t.Fatalf("expected 1 resolved package, got %d", len(resolved))
+	}
+	if resolved[0].SHA256 != wantSHA {
+		t.Errorf("SHA256 = %q, want %q", resolved[0].SHA256, wantSHA)
+	}
+	if !resolved[0].ResolvedAt.Equal(now) {
+		t.Errorf("ResolvedAt = %v, want %v", resolved[0].ResolvedAt, now)
+	}
+
+	if _, lockPath, err := loadLockFile(dir); err != nil {
+		t.Fatalf("failed to reload lock file at %s: %v", lockPath, err)
+	}
+}
+
+func TestResolveHardwarePackages_CachesAcrossCalls(t *testing.T) {
+	calls := 0
+	idx := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, `<a href="torch-%s-py3-none-any.whl#sha256=cafe">x</a>`, PyTorchSpec.Version)
+	}))
+	defer idx.Close()
+
+	spec := PyTorchSpec
+	spec.WheelURLs = map[HardwareCapability]string{HardwareCPU: idx.URL}
+	ServiceGraph["test-cache-service"] = Service{HardwarePackages: []PackageSpec{spec}}
+	defer delete(ServiceGraph, "test-cache-service")
+
+	dir := t.TempDir()
+	if _, err := ResolveHardwarePackages("test-cache-service", HardwareCPU, ResolveOptions{ConfigDir: dir}); err != nil {
+		t.Fatalf("first resolve failed: %v", err)
+	}
+	callsAfterFirst := calls
+
+	if _, err := ResolveHardwarePackages("test-cache-service", HardwareCPU, ResolveOptions{ConfigDir: dir}); err != nil {
+		t.Fatalf("second resolve failed: %v", err)
+	}
+	if calls != callsAfterFirst {
+		t.Errorf("expected no further index requests on a cached second call, got %d new request(s)", calls-callsAfterFirst)
+	}
+}
+
+func TestFetchPackageHash_NoMatchingVersion(t *testing.T) {
+	idx := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<a href="torch-0.0.1-py3-none-any.whl#sha256=abc">x</a>`)
+	}))
+	defer idx.Close()
+
+	if _, err := fetchPackageHash(http.DefaultClient, idx.URL, "torch", "9.9.9"); err == nil {
+		t.Fatal("expected an error when no link matches the requested version")
+	}
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// selfcheckCmd is a hidden sanity check spawned by performAtomicUpgrade
+// against a freshly installed binary: if it can't start up cleanly and
+// print its version, the upgrade is rolled back.
+var selfcheckCmd = &cobra.Command{
+	Use:    "__selfcheck",
+	Short:  "Internal: verify this binary starts up correctly",
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println(Version)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selfcheckCmd)
+}
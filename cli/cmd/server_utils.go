@@ -8,9 +8,10 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"os/exec"
 	"strings"
 	"time"
+
+	"llamafarm-cli/internal/trace"
 )
 
 // ensureServerAvailable verifies the server at serverURL is reachable.
@@ -27,6 +28,7 @@ func ensureServerAvailable(serverURL string) error {
 	}
 
 	if err := checkServerHealth(serverURL); err == nil {
+		trace.Log("ready", "server at %s already healthy", serverURL)
 		return nil
 	}
 
@@ -35,26 +37,25 @@ func ensureServerAvailable(serverURL string) error {
 		return fmt.Errorf("server %s is not reachable", serverURL)
 	}
 
+	trace.Log("ready", "server at %s not reachable, starting via docker", serverURL)
 	if err := startLocalServerViaDocker(serverURL); err != nil {
 		return err
 	}
 
-	// Poll for readiness
+	// Poll for readiness, backing off between attempts so a slow-starting
+	// server isn't hammered with requests every second.
 	timeout := serverStartTimeout
 	if timeout <= 0 {
 		timeout = 45 * time.Second
 	}
-	deadline := time.Now().Add(timeout)
-	for {
-		if err := checkServerHealth(serverURL); err == nil {
-			return nil
-		}
-		if time.Now().After(deadline) {
-			break
-		}
-		time.Sleep(1 * time.Second)
+	readyCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	check := func() error { return checkServerHealth(serverURL) }
+	if err := WaitForReadiness(readyCtx, check, DefaultReadinessPolicy); err != nil {
+		return fmt.Errorf("server at %s did not become ready: %w", serverURL, err)
 	}
-	return fmt.Errorf("server did not become ready at %s within timeout", serverURL)
+	return nil
 }
 
 // checkServerHealth pings the /info endpoint with a short timeout.
@@ -91,10 +92,13 @@ func isLocalhost(serverURL string) bool {
 	return host == "localhost" || host == "127.0.0.1" || host == "::1"
 }
 
-// startLocalServerViaDocker pulls and runs the LlamaFarm server container if needed.
-// It uses a fixed container name and maps the serverURL port to container port 8000.
+// startLocalServerViaDocker pulls and runs the LlamaFarm server container if
+// needed, via whichever container runtime backend LF_CONTAINER_RUNTIME
+// selects (default: auto-detect docker, podman, nerdctl, colima, or wsl).
+// It uses a fixed container name and maps the serverURL port to container
+// port 8000. The name is kept for source compatibility.
 func startLocalServerViaDocker(serverURL string) error {
-	// Ensure Docker is available
+	// Ensure a container runtime is available
 	if err := ensureDockerAvailable(); err != nil {
 		return err
 	}
@@ -108,57 +112,53 @@ func startLocalServerViaDocker(serverURL string) error {
 		return nil
 	}
 
-	fmt.Fprintln(os.Stderr, "Starting local LlamaFarm server via Docker...")
+	rotateServiceLogBeforeStart("server")
+
+	fmt.Fprintln(os.Stderr, "Starting local LlamaFarm server...")
 
 	// If a container with this name exists, remove it to ensure we always use the latest image
 	if containerExists(containerName) {
 		fmt.Fprintln(os.Stderr, "Removing existing LlamaFarm server container to ensure latest image and arguments...")
-		rmCmd := exec.Command("docker", "rm", "-f", containerName)
-		rmCmd.Stdout = os.Stdout
-		rmCmd.Stderr = os.Stderr
-		if err := rmCmd.Run(); err != nil {
+		if err := removeContainer(containerName); err != nil {
 			return fmt.Errorf("failed to remove existing container %s: %v", containerName, err)
 		}
 	}
 
-	// Pull latest image (best effort)
-	_ = pullImage(image)
-
-	// Run new container
-	runArgs := []string{
-		"run",
-		"-d",
-		"--name", containerName,
-		"-p", fmt.Sprintf("%d:8000", port),
-		"-v", fmt.Sprintf("%s:%s", os.ExpandEnv("$HOME/.llamafarm"), "/var/lib/llamafarm"),
+	spec := ContainerRunSpec{
+		Name:  containerName,
+		Image: image,
+		StaticPorts: []PortMapping{
+			{Host: port, Container: 8000, Protocol: "tcp"},
+		},
+		Volumes: []string{
+			fmt.Sprintf("%s:%s", os.ExpandEnv("$HOME/.llamafarm"), "/var/lib/llamafarm"),
+		},
+		Env: map[string]string{},
 	}
 
 	// Mount effective working directory into the container at the same path
 	if cwd := getEffectiveCWD(); strings.TrimSpace(cwd) != "" {
-		runArgs = append(runArgs, "-v", fmt.Sprintf("%s:%s", cwd, cwd))
+		spec.Volumes = append(spec.Volumes, fmt.Sprintf("%s:%s", cwd, cwd))
 	} else {
 		fmt.Fprintln(os.Stderr, "Warning: could not determine current directory; continuing without volume mount")
 	}
 
 	// Pass through or configure Ollama access inside the container
 	if isLocalhost(ollamaHost) {
-		port := resolvePort(ollamaHost, 11434)
-		runArgs = append(runArgs, "--add-host", "host.docker.internal:host-gateway")
-		runArgs = append(runArgs, "-e", fmt.Sprintf("OLLAMA_HOST=http://host.docker.internal:%d", port))
+		ollamaPort := resolvePort(ollamaHost, 11434)
+		spec.AddHosts = []string{"host.docker.internal:host-gateway"}
+		spec.Env["OLLAMA_HOST"] = fmt.Sprintf("http://host.docker.internal:%d", ollamaPort)
 	} else {
-		runArgs = append(runArgs, "-e", fmt.Sprintf("OLLAMA_HOST=%s", ollamaHost))
+		spec.Env["OLLAMA_HOST"] = ollamaHost
 	}
 
 	if v, ok := os.LookupEnv("OLLAMA_PORT"); ok && strings.TrimSpace(v) != "" {
-		runArgs = append(runArgs, "-e", fmt.Sprintf("OLLAMA_PORT=%s", v))
+		spec.Env["OLLAMA_PORT"] = v
 	}
 
-	// Image last
-	runArgs = append(runArgs, image)
-	runCmd := exec.Command("docker", runArgs...)
-	runOut, err := runCmd.CombinedOutput()
+	_, err := StartContainerDetachedWithPolicy(spec, &PortResolutionPolicy{PreferredHostPort: port, Forced: true})
 	if err != nil {
-		return fmt.Errorf("failed to start docker container: %v\n%s", err, string(runOut))
+		return fmt.Errorf("failed to start server container: %w", err)
 	}
 	return nil
 }
@@ -32,6 +32,11 @@ Available services:
   - rag: The RAG/Celery worker
   - universal-runtime: The universal runtime server
 
+Task runs (e.g. a specific RAG ingestion) can be selected alongside services,
+so you can correlate one ingestion run with the server logs in a single
+interleaved stream. Task logs are stored in
+~/.llamafarm/logs/tasks/<task-id>.log.
+
 Examples:
   lf services logs                           # Show logs from all services
   lf services logs --tail 50                 # Show last 50 lines from all services
@@ -39,47 +44,116 @@ Examples:
   lf services logs --service server          # Show all server logs
   lf services logs -s rag --tail 50          # Show last 50 lines of RAG logs
   lf services logs -s server --follow        # Follow server logs in real-time
-  lf services logs -s rag -f -n 100          # Follow RAG logs, starting with last 100 lines`,
+  lf services logs -s rag -f -n 100          # Follow RAG logs, starting with last 100 lines
+  lf services logs -s server,rag             # Combine multiple services in one stream
+  lf services logs -s server --task abc123   # Mix a service with a specific task run
+  lf services logs abc123                    # Positional args are treated as task IDs`,
 	Run: runServicesLogs,
 }
 
 func init() {
 	servicesCmd.AddCommand(servicesLogsCmd)
 
-	// Optional flag: which service to show logs for (if empty, show all)
-	servicesLogsCmd.Flags().StringP("service", "s", "", "Service to view logs for (server, rag, universal-runtime). If omitted, shows all services.")
+	// Optional flag: which service(s) to show logs for (if empty, show all).
+	// Repeatable (-s server -s rag) and comma-separated (-s server,rag) both work.
+	servicesLogsCmd.Flags().StringSliceP("service", "s", nil, "Service(s) to view logs for (server, rag, universal-runtime). If omitted, shows all services.")
+	servicesLogsCmd.Flags().StringSlice("task", nil, "Task/run ID(s) to view logs for, in addition to any --service selection.")
 
 	// Optional flags
 	servicesLogsCmd.Flags().BoolP("follow", "f", false, "Follow log output (like tail -f)")
 	servicesLogsCmd.Flags().IntP("tail", "n", 100, "Number of lines to show from the end (Default: 100, 0 = show all)")
+
+	// Filtering flags, applied to both plain and structured (JSON) log lines
+	servicesLogsCmd.Flags().String("level", "", "Only show lines at or above this level (debug, info, warn, error)")
+	servicesLogsCmd.Flags().String("since", "", "Only show lines at or after this time (RFC3339 timestamp or duration like 15m)")
+	servicesLogsCmd.Flags().String("until", "", "Only show lines at or before this time (RFC3339 timestamp or duration like 15m)")
+	servicesLogsCmd.Flags().String("grep", "", "Only show lines matching this regular expression")
+	servicesLogsCmd.Flags().StringArray("field", nil, "Only show structured lines with field=value (repeatable)")
+	servicesLogsCmd.Flags().String("output", "text", "Output format: text, json, or logfmt")
+
+	servicesLogsCmd.Flags().String("remote", "", "Stream logs from a LlamaFarm server's HTTP API instead of local files (e.g. http://my-server:8000). Auto-detected when --server-url points at a non-local or non-Native-orchestration server.")
+}
+
+// LogSelector identifies the combination of services and task runs whose
+// logs should be interleaved into one stream.
+type LogSelector struct {
+	Services []string
+	Tasks    []string
+}
+
+// Empty reports whether the selector picked nothing at all.
+func (s LogSelector) Empty() bool {
+	return len(s.Services) == 0 && len(s.Tasks) == 0
 }
 
 // runServicesLogs is the main entry point for the services logs command
 func runServicesLogs(cmd *cobra.Command, args []string) {
-	serviceName, _ := cmd.Flags().GetString("service")
+	printActiveTraceCategories()
+
+	serviceNames, _ := cmd.Flags().GetStringSlice("service")
+	taskIDs, _ := cmd.Flags().GetStringSlice("task")
 	follow, _ := cmd.Flags().GetBool("follow")
 	tailLines, _ := cmd.Flags().GetInt("tail")
 
-	// Determine which services to show logs for
+	level, _ := cmd.Flags().GetString("level")
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	grep, _ := cmd.Flags().GetString("grep")
+	fieldArgs, _ := cmd.Flags().GetStringArray("field")
+	outputFlag, _ := cmd.Flags().GetString("output")
+
+	filter, err := buildLogFilter(level, since, until, grep, fieldArgs)
+	if err != nil {
+		utils.OutputError("%v\n", err)
+		os.Exit(1)
+	}
+	format, err := parseOutputFormat(outputFlag)
+	if err != nil {
+		utils.OutputError("%v\n", err)
+		os.Exit(1)
+	}
+
+	// Positional args are treated as task IDs, e.g. `lf services logs <task-id>`.
+	taskIDs = append(taskIDs, args...)
+
 	validServices := []string{"server", "rag", "universal-runtime"}
-	var servicesToShow []string
+	selector := LogSelector{Services: serviceNames, Tasks: taskIDs}
 
-	if serviceName == "" {
-		// Show all services
-		servicesToShow = validServices
-	} else {
-		// Validate single service name
-		if !isValidService(serviceName, validServices) {
-			utils.OutputError("Invalid service: %s\n", serviceName)
+	if selector.Empty() {
+		// No explicit selection: show all services.
+		selector.Services = validServices
+	}
+
+	for _, svc := range selector.Services {
+		if !isValidService(svc, validServices) {
+			utils.OutputError("Invalid service: %s\n", svc)
 			fmt.Fprintf(os.Stderr, "Valid services are: %s\n", strings.Join(validServices, ", "))
 			os.Exit(1)
 		}
-		servicesToShow = []string{serviceName}
 	}
 
-	// Check which log files exist and build log file map
+	remoteFlag, _ := cmd.Flags().GetString("remote")
+	remoteServerURL := remoteFlag
+	useRemote := remoteFlag != ""
+	if !useRemote && strings.TrimSpace(serverURL) != "" && shouldUseRemoteLogs(serverURL) {
+		remoteServerURL = serverURL
+		useRemote = true
+	}
+
+	if useRemote {
+		if err := runServicesLogsRemote(remoteServerURL, selector, tailLines, follow, filter, format); err != nil {
+			utils.OutputError("Failed to stream remote logs: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	explicitSelection := len(selector.Services)+len(selector.Tasks) == 1
+
+	// Check which log files exist and build the label -> log file map that
+	// displayMultipleLogs/followMultipleLogs interleave.
 	logFiles := make(map[string]string)
-	for _, svc := range servicesToShow {
+	for _, svc := range selector.Services {
 		logFile, err := getServiceLogFile(svc)
 		if err != nil {
 			utils.OutputError("Failed to determine log file location for %s: %v\n", svc, err)
@@ -87,20 +161,38 @@ func runServicesLogs(cmd *cobra.Command, args []string) {
 		}
 
 		if _, err := os.Stat(logFile); os.IsNotExist(err) {
-			if len(servicesToShow) == 1 {
-				// Only error out if user specifically requested this service
+			if explicitSelection {
 				utils.OutputError("Log file not found: %s\n", logFile)
 				fmt.Fprintf(os.Stderr, "\nThe %s service may not have been started yet.\n", svc)
 				fmt.Fprintf(os.Stderr, "Run 'lf services start %s' to start the service.\n", svc)
 				os.Exit(1)
 			}
-			// Skip missing logs when showing all services
+			// Skip missing logs when showing a broader selection.
 			continue
 		}
 
 		logFiles[svc] = logFile
 	}
 
+	for _, taskID := range selector.Tasks {
+		logFile, err := getTaskLogFile(taskID)
+		if err != nil {
+			utils.OutputError("Failed to determine log file location for task %s: %v\n", taskID, err)
+			os.Exit(1)
+		}
+
+		if _, err := os.Stat(logFile); os.IsNotExist(err) {
+			if explicitSelection {
+				utils.OutputError("Log file not found: %s\n", logFile)
+				fmt.Fprintf(os.Stderr, "\nNo logs were recorded for task %s.\n", taskID)
+				os.Exit(1)
+			}
+			continue
+		}
+
+		logFiles["task:"+taskID] = logFile
+	}
+
 	if len(logFiles) == 0 {
 		utils.OutputError("No log files found for any service.\n")
 		fmt.Fprintf(os.Stderr, "\nServices may not have been started yet.\n")
@@ -111,14 +203,14 @@ func runServicesLogs(cmd *cobra.Command, args []string) {
 	// Display logs based on flags
 	if len(logFiles) == 1 {
 		// Single service - simple display without prefixes
-		for _, logFile := range logFiles {
+		for service, logFile := range logFiles {
 			if follow {
-				if err := followLogs(logFile, tailLines, ""); err != nil {
+				if err := followLogs(logFile, service, tailLines, filter, format); err != nil {
 					utils.OutputError("Failed to follow logs: %v\n", err)
 					os.Exit(1)
 				}
 			} else {
-				if err := displayLogs(logFile, tailLines, ""); err != nil {
+				if err := displayLogs(logFile, service, tailLines, filter, format); err != nil {
 					utils.OutputError("Failed to display logs: %v\n", err)
 					os.Exit(1)
 				}
@@ -127,12 +219,12 @@ func runServicesLogs(cmd *cobra.Command, args []string) {
 	} else {
 		// Multiple services - interleaved display with prefixes
 		if follow {
-			if err := followMultipleLogs(logFiles, tailLines); err != nil {
+			if err := followMultipleLogs(logFiles, tailLines, filter, format); err != nil {
 				utils.OutputError("Failed to follow logs: %v\n", err)
 				os.Exit(1)
 			}
 		} else {
-			if err := displayMultipleLogs(logFiles, tailLines); err != nil {
+			if err := displayMultipleLogs(logFiles, tailLines, filter, format); err != nil {
 				utils.OutputError("Failed to display logs: %v\n", err)
 				os.Exit(1)
 			}
@@ -163,8 +255,22 @@ func getServiceLogFile(serviceName string) (string, error) {
 	return logFile, nil
 }
 
+// getTaskLogFile returns the path to a specific task/run's log file, e.g. a
+// RAG ingestion run, as written by the worker that executed it.
+func getTaskLogFile(taskID string) (string, error) {
+	dataDir, err := utils.GetLFDataDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get llamafarm data directory: %w", err)
+	}
+
+	logsDir := filepath.Join(dataDir, "logs", "tasks")
+	logFile := filepath.Join(logsDir, fmt.Sprintf("%s.log", taskID))
+
+	return logFile, nil
+}
+
 // displayLogs displays logs from a file (optionally showing only the tail)
-func displayLogs(logFile string, tailLines int, prefix string) error {
+func displayLogs(logFile, service string, tailLines int, filter logFilter, format outputFormat) error {
 	file, err := os.Open(logFile)
 	if err != nil {
 		return fmt.Errorf("failed to open log file: %w", err)
@@ -173,23 +279,20 @@ func displayLogs(logFile string, tailLines int, prefix string) error {
 
 	// If tail is requested, read all lines and show only the last N
 	if tailLines > 0 {
-		return displayTailLines(file, tailLines, prefix)
+		return displayTailLines(file, service, tailLines, filter, format)
 	}
 
 	// Otherwise, show all lines
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		if prefix != "" {
-			fmt.Print(prefix)
-		}
-		fmt.Println(scanner.Text())
+		printLogLine(scanner.Text(), service, "", filter, format)
 	}
 
 	return scanner.Err()
 }
 
 // displayTailLines displays only the last N lines from a file
-func displayTailLines(file *os.File, n int, prefix string) error {
+func displayTailLines(file *os.File, service string, n int, filter logFilter, format outputFormat) error {
 	// Read all lines into a buffer
 	var lines []string
 	scanner := bufio.NewScanner(file)
@@ -209,17 +312,14 @@ func displayTailLines(file *os.File, n int, prefix string) error {
 
 	// Print the last N lines
 	for i := start; i < len(lines); i++ {
-		if prefix != "" {
-			fmt.Print(prefix)
-		}
-		fmt.Println(lines[i])
+		printLogLine(lines[i], service, "", filter, format)
 	}
 
 	return nil
 }
 
 // followLogs follows a log file in real-time (like tail -f)
-func followLogs(logFile string, initialTailLines int, prefix string) error {
+func followLogs(logFile, service string, initialTailLines int, filter logFilter, format outputFormat) error {
 	file, err := os.Open(logFile)
 	if err != nil {
 		return fmt.Errorf("failed to open log file: %w", err)
@@ -228,7 +328,7 @@ func followLogs(logFile string, initialTailLines int, prefix string) error {
 
 	// If tail is specified, first show the last N lines
 	if initialTailLines > 0 {
-		if err := displayTailLines(file, initialTailLines, prefix); err != nil {
+		if err := displayTailLines(file, service, initialTailLines, filter, format); err != nil {
 			return fmt.Errorf("failed to display initial tail: %w", err)
 		}
 	} else {
@@ -242,8 +342,13 @@ func followLogs(logFile string, initialTailLines int, prefix string) error {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	// Create a reader that will follow the file
-	reader := bufio.NewReader(file)
+	// Wrap the file in a reader that transparently reopens it if logrotate
+	// rotates it out from under us.
+	rr, err := newRotatingReader(logFile, file)
+	if err != nil {
+		return fmt.Errorf("failed to follow log file: %w", err)
+	}
+	defer rr.Close()
 
 	// Channel to signal when we should stop
 	done := make(chan struct{})
@@ -263,9 +368,10 @@ func followLogs(logFile string, initialTailLines int, prefix string) error {
 		case <-done:
 			return nil
 		case <-ticker.C:
+			rr.checkRotation()
 			// Try to read lines
 			for {
-				line, err := reader.ReadString('\n')
+				line, err := rr.readLine()
 				if err != nil {
 					if err == io.EOF {
 						// No more data available right now
@@ -273,26 +379,78 @@ func followLogs(logFile string, initialTailLines int, prefix string) error {
 					}
 					return fmt.Errorf("failed to read from log file: %w", err)
 				}
-				// Print the line with prefix (already includes newline)
-				if prefix != "" {
-					fmt.Print(prefix)
-				}
-				fmt.Print(line)
+				printLogLine(strings.TrimRight(line, "\n"), service, "", filter, format)
 			}
 		}
 	}
 }
 
-// logLine represents a line from a log file with metadata
-type logLine struct {
-	timestamp time.Time
-	service   string
-	content   string
+// printLogLine parses raw against service, and if it passes filter, renders
+// it to stdout with prefix according to format.
+func printLogLine(raw, service, prefix string, filter logFilter, format outputFormat) {
+	pl := parseLogLine(raw, service)
+	if !filter.matches(pl) {
+		return
+	}
+	fmt.Println(pl.render(format, prefix))
+}
+
+// rotatingReader follows a log file path, transparently reopening it if
+// logrotate (or an external process) rotates it out from under us — detected
+// via inode change or the file shrinking below our last read offset — so
+// `-f` survives rotation instead of following a deleted file descriptor.
+type rotatingReader struct {
+	path   string
+	file   *os.File
+	reader *bufio.Reader
+	info   os.FileInfo
+}
+
+func newRotatingReader(path string, file *os.File) (*rotatingReader, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	return &rotatingReader{path: path, file: file, reader: bufio.NewReader(file), info: info}, nil
+}
+
+// checkRotation reopens the underlying file from the start if it was
+// rotated or truncated since the last check.
+func (r *rotatingReader) checkRotation() {
+	newInfo, err := os.Stat(r.path)
+	if err != nil {
+		// Rotation may be in progress (brief unlink); retry next tick.
+		return
+	}
+
+	pos, _ := r.file.Seek(0, io.SeekCurrent)
+	rotated := !os.SameFile(r.info, newInfo)
+	truncated := !rotated && newInfo.Size() < pos
+	if !rotated && !truncated {
+		return
+	}
+
+	newFile, err := os.Open(r.path)
+	if err != nil {
+		return
+	}
+	r.file.Close()
+	r.file = newFile
+	r.reader = bufio.NewReader(newFile)
+	r.info = newInfo
+}
+
+func (r *rotatingReader) readLine() (string, error) {
+	return r.reader.ReadString('\n')
+}
+
+func (r *rotatingReader) Close() error {
+	return r.file.Close()
 }
 
 // displayMultipleLogs displays logs from multiple files, interleaved by timestamp
-func displayMultipleLogs(logFiles map[string]string, tailLines int) error {
-	var allLines []logLine
+func displayMultipleLogs(logFiles map[string]string, tailLines int, filter logFilter, format outputFormat) error {
+	var allLines []parsedLogLine
 
 	// Read lines from all files
 	for service, logFile := range logFiles {
@@ -303,13 +461,11 @@ func displayMultipleLogs(logFiles map[string]string, tailLines int) error {
 
 		scanner := bufio.NewScanner(file)
 		for scanner.Scan() {
-			line := scanner.Text()
-			timestamp := extractTimestamp(line)
-			allLines = append(allLines, logLine{
-				timestamp: timestamp,
-				service:   service,
-				content:   line,
-			})
+			pl := parseLogLine(scanner.Text(), service)
+			if pl.timestamp.IsZero() {
+				pl.timestamp = extractTimestamp(pl.raw)
+			}
+			allLines = append(allLines, pl)
 		}
 		file.Close()
 
@@ -328,20 +484,22 @@ func displayMultipleLogs(logFiles map[string]string, tailLines int) error {
 		allLines = allLines[len(allLines)-tailLines:]
 	}
 
-	// Print all lines with service prefix
-	for _, line := range allLines {
-		prefix := getServicePrefix(line.service)
-		fmt.Printf("%s%s\n", prefix, line.content)
+	// Print matching lines with service prefix
+	for _, pl := range allLines {
+		if !filter.matches(pl) {
+			continue
+		}
+		fmt.Println(pl.render(format, getServicePrefix(pl.service)))
 	}
 
 	return nil
 }
 
 // followMultipleLogs follows multiple log files in real-time
-func followMultipleLogs(logFiles map[string]string, initialTailLines int) error {
+func followMultipleLogs(logFiles map[string]string, initialTailLines int, filter logFilter, format outputFormat) error {
 	// First, show initial tail if requested
 	if initialTailLines > 0 {
-		if err := displayMultipleLogs(logFiles, initialTailLines); err != nil {
+		if err := displayMultipleLogs(logFiles, initialTailLines, filter, format); err != nil {
 			return err
 		}
 	}
@@ -358,18 +516,14 @@ func followMultipleLogs(logFiles map[string]string, initialTailLines int) error
 	}()
 
 	// Open all files and seek to end
-	type fileInfo struct {
-		file   *os.File
-		reader *bufio.Reader
-	}
-	files := make(map[string]*fileInfo)
+	readers := make(map[string]*rotatingReader)
 
 	for service, logFile := range logFiles {
 		file, err := os.Open(logFile)
 		if err != nil {
 			// Close any already opened files before returning
-			for _, fi := range files {
-				fi.file.Close()
+			for _, rr := range readers {
+				rr.Close()
 			}
 			return fmt.Errorf("failed to open log file for %s: %w", service, err)
 		}
@@ -379,23 +533,28 @@ func followMultipleLogs(logFiles map[string]string, initialTailLines int) error
 			if _, err := file.Seek(0, io.SeekEnd); err != nil {
 				file.Close()
 				// Close any already opened files before returning
-				for _, fi := range files {
-					fi.file.Close()
+				for _, rr := range readers {
+					rr.Close()
 				}
 				return fmt.Errorf("failed to seek to end for %s: %w", service, err)
 			}
 		}
 
-		files[service] = &fileInfo{
-			file:   file,
-			reader: bufio.NewReader(file),
+		rr, err := newRotatingReader(logFile, file)
+		if err != nil {
+			file.Close()
+			for _, other := range readers {
+				other.Close()
+			}
+			return fmt.Errorf("failed to follow log file for %s: %w", service, err)
 		}
+		readers[service] = rr
 	}
 
 	// Defer closing all files
 	defer func() {
-		for _, fi := range files {
-			fi.file.Close()
+		for _, rr := range readers {
+			rr.Close()
 		}
 	}()
 
@@ -404,9 +563,9 @@ func followMultipleLogs(logFiles map[string]string, initialTailLines int) error
 
 	// Start a goroutine for each service
 	var wg sync.WaitGroup
-	for service, fi := range files {
+	for service, rr := range readers {
 		wg.Add(1)
-		go func(svc string, info *fileInfo) {
+		go func(svc string, rr *rotatingReader) {
 			defer wg.Done()
 			prefix := getServicePrefix(svc)
 			ticker := time.NewTicker(100 * time.Millisecond)
@@ -417,21 +576,26 @@ func followMultipleLogs(logFiles map[string]string, initialTailLines int) error
 				case <-done:
 					return
 				case <-ticker.C:
+					rr.checkRotation()
 					for {
-						line, err := info.reader.ReadString('\n')
+						line, err := rr.readLine()
 						if err != nil {
 							if err == io.EOF {
 								break
 							}
 							return
 						}
+						pl := parseLogLine(strings.TrimRight(line, "\n"), svc)
+						if !filter.matches(pl) {
+							continue
+						}
 						outputMu.Lock()
-						fmt.Printf("%s%s", prefix, line)
+						fmt.Println(pl.render(format, prefix))
 						outputMu.Unlock()
 					}
 				}
 			}
-		}(service, fi)
+		}(service, rr)
 	}
 
 	wg.Wait()
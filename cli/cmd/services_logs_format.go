@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logLevelOrder ranks levels from least to most severe so --level can act as
+// a minimum-severity filter regardless of which logging library emitted the
+// line (logrus, zap and zerolog all agree on these four).
+var logLevelOrder = map[string]int{
+	"debug":   0,
+	"info":    1,
+	"warn":    2,
+	"warning": 2,
+	"error":   3,
+}
+
+// logLevelColor gives each level a distinct ANSI color for text rendering.
+var logLevelColor = map[string]string{
+	"debug": "\033[90m", // gray
+	"info":  "\033[36m", // cyan
+	"warn":  "\033[33m", // yellow
+	"error": "\033[31m", // red
+}
+
+const ansiReset = "\033[0m"
+
+// logFilter holds the --level/--since/--until/--grep/--field criteria a
+// parsed line must satisfy to be displayed.
+type logFilter struct {
+	minLevel string
+	since    time.Time
+	until    time.Time
+	grep     *regexp.Regexp
+	fields   map[string]string
+}
+
+// outputFormat controls how a matching line is rendered.
+type outputFormat string
+
+const (
+	outputText   outputFormat = "text"
+	outputJSON   outputFormat = "json"
+	outputLogfmt outputFormat = "logfmt"
+)
+
+// parseOutputFormat validates --output, defaulting to text.
+func parseOutputFormat(value string) (outputFormat, error) {
+	switch outputFormat(strings.ToLower(strings.TrimSpace(value))) {
+	case "", outputText:
+		return outputText, nil
+	case outputJSON:
+		return outputJSON, nil
+	case outputLogfmt:
+		return outputLogfmt, nil
+	default:
+		return "", fmt.Errorf("invalid --output %q (want json, logfmt, or text)", value)
+	}
+}
+
+// buildLogFilter parses the filtering flags into a logFilter. An empty
+// logFilter matches every line.
+func buildLogFilter(level, since, until, grep string, fieldArgs []string) (logFilter, error) {
+	f := logFilter{minLevel: strings.ToLower(strings.TrimSpace(level))}
+
+	if f.minLevel != "" {
+		if _, ok := logLevelOrder[f.minLevel]; !ok {
+			return logFilter{}, fmt.Errorf("invalid --level %q (want debug, info, warn, or error)", level)
+		}
+	}
+
+	if since != "" {
+		t, err := parseTimeOrDuration(since)
+		if err != nil {
+			return logFilter{}, fmt.Errorf("invalid --since %q: %w", since, err)
+		}
+		f.since = t
+	}
+	if until != "" {
+		t, err := parseTimeOrDuration(until)
+		if err != nil {
+			return logFilter{}, fmt.Errorf("invalid --until %q: %w", until, err)
+		}
+		f.until = t
+	}
+
+	if grep != "" {
+		re, err := regexp.Compile(grep)
+		if err != nil {
+			return logFilter{}, fmt.Errorf("invalid --grep pattern %q: %w", grep, err)
+		}
+		f.grep = re
+	}
+
+	if len(fieldArgs) > 0 {
+		f.fields = make(map[string]string, len(fieldArgs))
+		for _, kv := range fieldArgs {
+			key, val, ok := strings.Cut(kv, "=")
+			if !ok {
+				return logFilter{}, fmt.Errorf("invalid --field %q (want key=value)", kv)
+			}
+			f.fields[key] = val
+		}
+	}
+
+	return f, nil
+}
+
+// parseTimeOrDuration accepts either an RFC3339 timestamp or a duration
+// relative to now (e.g. "15m", "2h"), matching the common `--since` UX of
+// tools like `docker logs` and `kubectl logs`.
+func parseTimeOrDuration(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("must be an RFC3339 timestamp or a duration like \"15m\"")
+}
+
+// parsedLogLine is a log line normalized from either plain or structured
+// (JSON) form, so interleaved multi-service output can be filtered and
+// rendered uniformly regardless of which backend emitted the line.
+type parsedLogLine struct {
+	timestamp time.Time
+	service   string
+	level     string
+	msg       string
+	fields    map[string]string
+	raw       string
+}
+
+// parseLogLine detects structured JSON log lines (as emitted by
+// logrus/zap/zerolog) in addition to the plain `[timestamp] ...` format, and
+// normalizes both into a parsedLogLine.
+func parseLogLine(raw, service string) parsedLogLine {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "{") {
+		if pl, ok := parseJSONLogLine(trimmed, service, raw); ok {
+			return pl
+		}
+	}
+
+	return parsedLogLine{
+		timestamp: extractTimestamp(raw),
+		service:   service,
+		msg:       raw,
+		raw:       raw,
+	}
+}
+
+// parseJSONLogLine attempts to parse raw as a structured log entry with
+// time/level/msg fields plus arbitrary extra fields.
+func parseJSONLogLine(trimmed, service, raw string) (parsedLogLine, bool) {
+	var generic map[string]interface{}
+	if err := json.Unmarshal([]byte(trimmed), &generic); err != nil {
+		return parsedLogLine{}, false
+	}
+
+	pl := parsedLogLine{service: service, raw: raw, fields: map[string]string{}}
+
+	for key, value := range generic {
+		switch strings.ToLower(key) {
+		case "time", "timestamp", "ts", "@timestamp":
+			pl.timestamp = parseAnyTimestamp(value)
+		case "level", "lvl", "severity":
+			pl.level = strings.ToLower(fmt.Sprintf("%v", value))
+		case "msg", "message":
+			pl.msg = fmt.Sprintf("%v", value)
+		default:
+			pl.fields[key] = fmt.Sprintf("%v", value)
+		}
+	}
+
+	if pl.msg == "" {
+		pl.msg = raw
+	}
+	return pl, true
+}
+
+// parseAnyTimestamp handles both RFC3339 strings and numeric (unix seconds
+// or milliseconds) timestamps, since different logging libraries emit
+// different shapes for "time".
+func parseAnyTimestamp(value interface{}) time.Time {
+	switch v := value.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+	case float64:
+		if v > 1e12 {
+			return time.UnixMilli(int64(v))
+		}
+		return time.Unix(int64(v), 0)
+	}
+	return time.Time{}
+}
+
+// matches reports whether pl satisfies every configured criterion in f.
+func (f logFilter) matches(pl parsedLogLine) bool {
+	if f.minLevel != "" {
+		level := pl.level
+		if level == "" {
+			// Lines with no detected level (plain-format logs) are never
+			// filtered out by --level, since we can't know their severity.
+		} else if rank, ok := logLevelOrder[level]; !ok || rank < logLevelOrder[f.minLevel] {
+			return false
+		}
+	}
+
+	if !f.since.IsZero() && !pl.timestamp.IsZero() && pl.timestamp.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && !pl.timestamp.IsZero() && pl.timestamp.After(f.until) {
+		return false
+	}
+
+	if f.grep != nil && !f.grep.MatchString(pl.raw) {
+		return false
+	}
+
+	for key, want := range f.fields {
+		if got, ok := pl.fields[key]; !ok || got != want {
+			return false
+		}
+	}
+
+	return true
+}
+
+// render formats pl for display according to format, with per-level ANSI
+// colors in text mode.
+func (pl parsedLogLine) render(format outputFormat, prefix string) string {
+	switch format {
+	case outputJSON:
+		return pl.renderJSON()
+	case outputLogfmt:
+		return prefix + pl.renderLogfmt()
+	default:
+		return prefix + pl.renderText()
+	}
+}
+
+func (pl parsedLogLine) renderJSON() string {
+	out := map[string]interface{}{
+		"service": pl.service,
+		"msg":     pl.msg,
+	}
+	if !pl.timestamp.IsZero() {
+		out["time"] = pl.timestamp.Format(time.RFC3339)
+	}
+	if pl.level != "" {
+		out["level"] = pl.level
+	}
+	for k, v := range pl.fields {
+		out[k] = v
+	}
+	encoded, err := json.Marshal(out)
+	if err != nil {
+		return pl.raw
+	}
+	return string(encoded)
+}
+
+func (pl parsedLogLine) renderLogfmt() string {
+	var b strings.Builder
+	if !pl.timestamp.IsZero() {
+		fmt.Fprintf(&b, "time=%s ", pl.timestamp.Format(time.RFC3339))
+	}
+	if pl.level != "" {
+		fmt.Fprintf(&b, "level=%s ", pl.level)
+	}
+	fmt.Fprintf(&b, "service=%s msg=%s", pl.service, strconv.Quote(pl.msg))
+	for _, k := range sortedKeys(pl.fields) {
+		fmt.Fprintf(&b, " %s=%s", k, strconv.Quote(pl.fields[k]))
+	}
+	return b.String()
+}
+
+func (pl parsedLogLine) renderText() string {
+	var b strings.Builder
+	if !pl.timestamp.IsZero() {
+		fmt.Fprintf(&b, "%s ", pl.timestamp.Format("2006-01-02 15:04:05"))
+	}
+	if pl.level != "" {
+		color, ok := logLevelColor[pl.level]
+		if ok {
+			fmt.Fprintf(&b, "%s%-5s%s ", color, strings.ToUpper(pl.level), ansiReset)
+		} else {
+			fmt.Fprintf(&b, "%-5s ", strings.ToUpper(pl.level))
+		}
+	}
+	fmt.Fprintf(&b, "[%s] %s", pl.service, pl.msg)
+	for _, k := range sortedKeys(pl.fields) {
+		fmt.Fprintf(&b, " %s=%s", k, pl.fields[k])
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// shouldUseRemoteLogs reports whether `lf services logs` should stream from
+// the server's HTTP API instead of reading local files: either the target
+// server isn't on this machine, or orchestration isn't Native (so service
+// logs live inside whatever container/host is actually running them, not
+// under this machine's ~/.llamafarm/logs).
+func shouldUseRemoteLogs(serverURL string) bool {
+	if !isLocalhost(serverURL) {
+		return true
+	}
+	return determineOrchestrationMode() != OrchestrationNative
+}
+
+// runServicesLogsRemote streams logs for the selected services/tasks from
+// the server's /api/{services,tasks}/{name}/logs SSE endpoint, applying the
+// same filter/format as the local path. Each stream reconnects with
+// exponential backoff on transient errors and is serialized through a
+// shared mutex so interleaved output from multiple services doesn't tear
+// mid-line.
+func runServicesLogsRemote(serverURL string, selector LogSelector, tailLines int, follow bool, filter logFilter, format outputFormat) error {
+	if err := checkServerHealth(serverURL); err != nil {
+		readyCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		check := func() error { return checkServerHealth(serverURL) }
+		if err := WaitForReadiness(readyCtx, check, DefaultReadinessPolicy); err != nil {
+			return fmt.Errorf("server at %s is not reachable: %w", serverURL, err)
+		}
+	}
+
+	services := selector.Services
+	tasks := selector.Tasks
+	if len(services) == 0 && len(tasks) == 0 {
+		services = []string{"server", "rag", "universal-runtime"}
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		<-sigChan
+		close(done)
+	}()
+
+	multiple := len(services)+len(tasks) > 1
+
+	var outputMu sync.Mutex
+	var wg sync.WaitGroup
+
+	startStream := func(kind, name string) {
+		defer wg.Done()
+		prefix := ""
+		if multiple {
+			prefix = getServicePrefix(name)
+		}
+		streamRemoteLogWithBackoff(serverURL, kind, name, tailLines, follow, filter, format, prefix, done, &outputMu)
+	}
+
+	for _, svc := range services {
+		wg.Add(1)
+		go startStream("services", svc)
+	}
+	for _, taskID := range tasks {
+		wg.Add(1)
+		go startStream("tasks", taskID)
+	}
+
+	wg.Wait()
+	return nil
+}
+
+// streamRemoteLogWithBackoff keeps a single service/task's SSE stream alive,
+// reconnecting with exponential backoff (mirroring DefaultReadinessPolicy)
+// whenever the connection drops while --follow is set.
+func streamRemoteLogWithBackoff(serverURL, kind, name string, tailLines int, follow bool, filter logFilter, format outputFormat, prefix string, done chan struct{}, outputMu *sync.Mutex) {
+	backoff := DefaultReadinessPolicy.InitialInterval
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		err := consumeRemoteLogStream(serverURL, kind, name, tailLines, follow, filter, format, prefix, done, outputMu)
+		if err == nil || !follow {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%sstream ended: %v\n", prefix, err)
+			}
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "%sstream disconnected (%v), reconnecting in %s...\n", prefix, err, backoff)
+		select {
+		case <-done:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = time.Duration(float64(backoff) * DefaultReadinessPolicy.Multiplier)
+		if backoff > DefaultReadinessPolicy.MaxInterval {
+			backoff = DefaultReadinessPolicy.MaxInterval
+		}
+	}
+}
+
+// remoteLogURL builds the SSE request URL for a single service/task.
+func remoteLogURL(serverURL, kind, name string, tailLines int, follow bool, filter logFilter) string {
+	base := strings.TrimRight(serverURL, "/")
+	reqURL := fmt.Sprintf("%s/api/%s/%s/logs", base, kind, url.PathEscape(name))
+
+	q := url.Values{}
+	q.Set("follow", strconv.FormatBool(follow))
+	if tailLines > 0 {
+		q.Set("tail", strconv.Itoa(tailLines))
+	}
+	if !filter.since.IsZero() {
+		q.Set("since", filter.since.Format(time.RFC3339))
+	}
+	return reqURL + "?" + q.Encode()
+}
+
+// consumeRemoteLogStream reads one SSE connection to completion (or until
+// done is closed), printing matching lines as they arrive. A "data: ..."
+// line carries one raw log line, same as would have been written locally.
+func consumeRemoteLogStream(serverURL, kind, name string, tailLines int, follow bool, filter logFilter, format outputFormat, prefix string, done chan struct{}, outputMu *sync.Mutex) error {
+	reqURL := remoteLogURL(serverURL, kind, name, tailLines, follow, filter)
+
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d streaming logs for %s", resp.StatusCode, name)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue // skip SSE "event:"/"id:"/blank framing lines
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "" {
+			continue
+		}
+
+		pl := parseLogLine(data, name)
+		if !filter.matches(pl) {
+			continue
+		}
+		outputMu.Lock()
+		fmt.Println(pl.render(format, prefix))
+		outputMu.Unlock()
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"llamafarm-cli/cmd/logrotate"
+
+	"github.com/llamafarm/cli/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+// servicesLogsRotateCmd manually rotates service/task log files using the
+// same thresholds applied automatically before a service starts writing.
+var servicesLogsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate and prune service log files",
+	Long: `Rotate service (and optionally task) log files that have grown past
+--max-size or --max-age, shifting existing backups up and deleting anything
+past --max-backups. With no --service/--task flags, all known services are
+checked.`,
+	Run: runServicesLogsRotate,
+}
+
+func init() {
+	servicesLogsCmd.AddCommand(servicesLogsRotateCmd)
+
+	servicesLogsRotateCmd.Flags().StringSliceP("service", "s", nil, "Service(s) to rotate logs for. If omitted, rotates all services.")
+	servicesLogsRotateCmd.Flags().StringSlice("task", nil, "Task/run ID(s) to rotate logs for, in addition to any --service selection.")
+	servicesLogsRotateCmd.Flags().String("max-size", "100MB", "Rotate once the log file reaches this size (e.g. 100MB, 1GB)")
+	servicesLogsRotateCmd.Flags().String("max-age", "168h", "Rotate once the log file is older than this (e.g. 168h, 7d)")
+	servicesLogsRotateCmd.Flags().Int("max-backups", logrotate.DefaultPolicy.MaxBackups, "Number of rotated backups to retain")
+	servicesLogsRotateCmd.Flags().Bool("compress", false, "Gzip rotated backups")
+}
+
+func runServicesLogsRotate(cmd *cobra.Command, args []string) {
+	serviceNames, _ := cmd.Flags().GetStringSlice("service")
+	taskIDs, _ := cmd.Flags().GetStringSlice("task")
+	maxSizeStr, _ := cmd.Flags().GetString("max-size")
+	maxAgeStr, _ := cmd.Flags().GetString("max-age")
+	maxBackups, _ := cmd.Flags().GetInt("max-backups")
+	compress, _ := cmd.Flags().GetBool("compress")
+
+	maxSize, err := parseSize(maxSizeStr)
+	if err != nil {
+		utils.OutputError("Invalid --max-size %q: %v\n", maxSizeStr, err)
+		os.Exit(1)
+	}
+	maxAge, err := parseDayDuration(maxAgeStr)
+	if err != nil {
+		utils.OutputError("Invalid --max-age %q: %v\n", maxAgeStr, err)
+		os.Exit(1)
+	}
+
+	policy := logrotate.Policy{
+		MaxSizeBytes: maxSize,
+		MaxAge:       maxAge,
+		MaxBackups:   maxBackups,
+		Compress:     compress,
+	}
+
+	taskIDs = append(taskIDs, args...)
+	if len(serviceNames) == 0 && len(taskIDs) == 0 {
+		serviceNames = []string{"server", "rag", "universal-runtime"}
+	}
+
+	rotated := 0
+	for _, svc := range serviceNames {
+		logFile, err := getServiceLogFile(svc)
+		if err != nil {
+			utils.OutputError("Failed to determine log file location for %s: %v\n", svc, err)
+			os.Exit(1)
+		}
+		if didRotate, err := logrotate.RotateIfNeeded(logFile, policy); err != nil {
+			utils.OutputError("Failed to rotate logs for %s: %v\n", svc, err)
+			os.Exit(1)
+		} else if didRotate {
+			fmt.Printf("Rotated %s\n", logFile)
+			rotated++
+		}
+	}
+
+	for _, taskID := range taskIDs {
+		logFile, err := getTaskLogFile(taskID)
+		if err != nil {
+			utils.OutputError("Failed to determine log file location for task %s: %v\n", taskID, err)
+			os.Exit(1)
+		}
+		if didRotate, err := logrotate.RotateIfNeeded(logFile, policy); err != nil {
+			utils.OutputError("Failed to rotate logs for task %s: %v\n", taskID, err)
+			os.Exit(1)
+		} else if didRotate {
+			fmt.Printf("Rotated %s\n", logFile)
+			rotated++
+		}
+	}
+
+	if rotated == 0 {
+		fmt.Println("No log files were due for rotation.")
+	}
+}
+
+// rotateServiceLogBeforeStart rotates serviceName's log file if it's due,
+// using logrotate.PolicyFromEnv, before the service starts writing to it
+// again. Rotation failures are reported but never block the service from
+// starting - a log file that can't be rotated shouldn't stop `lf dev`.
+func rotateServiceLogBeforeStart(serviceName string) {
+	logFile, err := getServiceLogFile(serviceName)
+	if err != nil {
+		utils.OutputError("Failed to determine log file location for %s: %v\n", serviceName, err)
+		return
+	}
+	if _, err := logrotate.RotateIfNeeded(logFile, logrotate.PolicyFromEnv()); err != nil {
+		utils.OutputError("Failed to rotate logs for %s: %v\n", serviceName, err)
+	}
+}
+
+// parseSize parses a human size like "100MB" or "1GB" into bytes.
+func parseSize(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	units := map[string]int64{
+		"B":  1,
+		"KB": 1024,
+		"MB": 1024 * 1024,
+		"GB": 1024 * 1024 * 1024,
+	}
+
+	upper := strings.ToUpper(value)
+	for _, suffix := range []string{"GB", "MB", "KB", "B"} {
+		if strings.HasSuffix(upper, suffix) {
+			numPart := strings.TrimSpace(strings.TrimSuffix(upper, suffix))
+			n, err := strconv.ParseFloat(numPart, 64)
+			if err != nil {
+				return 0, fmt.Errorf("expected a number followed by B/KB/MB/GB")
+			}
+			return int64(n * float64(units[suffix])), nil
+		}
+	}
+
+	n, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number followed by B/KB/MB/GB")
+	}
+	return n, nil
+}
+
+// parseDayDuration extends time.ParseDuration with a "d" (day) unit, since
+// --max-age is commonly expressed in days (e.g. "7d").
+func parseDayDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasSuffix(value, "d") {
+		numPart := strings.TrimSuffix(value, "d")
+		days, err := strconv.ParseFloat(numPart, 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected a duration like \"7d\" or \"168h\"")
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,240 @@
+// Package sigstore implements a minimal, self-contained verifier for
+// cosign/sigstore-style release signatures: a short-lived signing
+// certificate issued by Fulcio (verified against an embedded CA root), the
+// detached signature it made over a release artifact, and a Rekor
+// transparency-log inclusion proof tying the artifact's hash to a point in time.
+// It deliberately avoids depending on the upstream cosign/sigstore-go client
+// libraries so the CLI's upgrade path doesn't inherit their dependency
+// tree, and is written generically enough to be reused for verifying
+// container image signatures later, not just `lf` binaries.
+package sigstore
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	_ "embed"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"llamafarm-cli/cmd/transparency"
+)
+
+// embeddedFulcioRootPEM is the trusted CA root that every release signing
+// certificate must chain to. Rotating Fulcio's root requires a new `lf`
+// release with an updated fulcio_root.pem here, the same distribution model
+// as tufclient's embedded TUF root.
+//
+//go:embed fulcio_root.pem
+var embeddedFulcioRootPEM []byte
+
+// fulcioIssuerOID is the X.509 extension Fulcio stamps on every certificate
+// it issues, recording the OIDC issuer that authenticated the signer.
+var fulcioIssuerOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+
+// Bundle is the signing material published alongside a release artifact:
+// the Fulcio-issued signing certificate (PEM) and the base64-encoded
+// detached signature it made over the artifact.
+type Bundle struct {
+	CertPEM      []byte
+	SignatureB64 string
+}
+
+// Identity is the signer identity extracted from a verified certificate.
+type Identity struct {
+	// Subject is the certificate's SAN, e.g. a GitHub Actions workflow ref
+	// such as "https://github.com/llama-farm/llamafarm/.github/workflows/release.yml@refs/heads/main".
+	Subject string
+	// Issuer is the OIDC provider that authenticated the signer, e.g.
+	// "https://token.actions.githubusercontent.com".
+	Issuer string
+}
+
+// FetchBundle downloads the detached signature and signing certificate
+// published alongside assetURL (at assetURL+".sig" and assetURL+".pem").
+func FetchBundle(client *http.Client, assetURL string) (*Bundle, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	certPEM, err := fetch(client, assetURL+".pem")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing certificate: %w", err)
+	}
+	sigBytes, err := fetch(client, assetURL+".sig")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signature: %w", err)
+	}
+
+	return &Bundle{CertPEM: certPEM, SignatureB64: string(sigBytes)}, nil
+}
+
+func fetch(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, url)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// VerifyArtifact confirms that bundle's certificate chains to the embedded
+// Fulcio root, that its signature over artifactPath is valid, and that the
+// signer's identity matches allowedIssuer/allowedIdentity. When rekorURL is
+// non-empty it then checks that the artifact's SHA-256 is recorded in the
+// Rekor log at rekorURL before returning the verified identity; the
+// signature itself is not part of that log lookup.
+func VerifyArtifact(artifactPath string, bundle *Bundle, rekorURL, allowedIssuer, allowedIdentity string) (*Identity, error) {
+	leaf, err := verifyCertChain(bundle.CertPEM)
+	if err != nil {
+		return nil, fmt.Errorf("certificate chain verification failed: %w", err)
+	}
+
+	identity, err := extractIdentity(leaf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract signer identity: %w", err)
+	}
+	if err := checkIdentity(identity, allowedIssuer, allowedIdentity); err != nil {
+		return nil, err
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(bundle.SignatureB64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	digest, err := sha256File(artifactPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash artifact: %w", err)
+	}
+
+	pub, ok := leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("signing certificate does not use an ECDSA key")
+	}
+	if !ecdsa.VerifyASN1(pub, digest, sig) {
+		return nil, fmt.Errorf("signature does not match artifact")
+	}
+
+	if rekorURL != "" {
+		artifactSHA256 := fmt.Sprintf("%x", digest)
+		proof, sth, err := transparency.FetchInclusionProof(nil, rekorURL, artifactSHA256)
+		if err != nil {
+			return nil, fmt.Errorf("rekor inclusion proof fetch failed: %w", err)
+		}
+		leafHash := transparency.HashLeaf([]byte(artifactSHA256))
+		ok, err := transparency.VerifyInclusion(leafHash, *proof, *sth)
+		if err != nil {
+			return nil, fmt.Errorf("rekor inclusion proof invalid: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("rekor log does not include artifact %s", artifactSHA256)
+		}
+	}
+
+	return identity, nil
+}
+
+// verifyCertChain parses certPEM and verifies it chains to the embedded
+// Fulcio root, returning the leaf (signing) certificate.
+func verifyCertChain(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in signing certificate")
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing certificate: %w", err)
+	}
+
+	rootBlock, _ := pem.Decode(embeddedFulcioRootPEM)
+	if rootBlock == nil {
+		return nil, fmt.Errorf("embedded Fulcio root is invalid")
+	}
+	root, err := x509.ParseCertificate(rootBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("embedded Fulcio root is invalid: %w", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	// Fulcio certificates are short-lived (minutes) and are only ever valid
+	// at the moment they were issued; verify the chain as of the
+	// certificate's own validity window rather than the current time.
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: leaf.NotBefore.Add(time.Minute),
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning, x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, err
+	}
+
+	return leaf, nil
+}
+
+// extractIdentity reads the OIDC issuer extension and SAN that Fulcio
+// stamps onto every certificate it issues.
+func extractIdentity(cert *x509.Certificate) (*Identity, error) {
+	var issuer string
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(fulcioIssuerOID) {
+			// ext.Value is the DER encoding of the extension (an ASN.1
+			// UTF8String here), not a plain Go string - it must be
+			// unmarshaled, not byte-cast.
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err != nil {
+				return nil, fmt.Errorf("invalid Fulcio issuer extension: %w", err)
+			}
+		}
+	}
+	if issuer == "" {
+		return nil, fmt.Errorf("certificate has no Fulcio OIDC issuer extension")
+	}
+
+	var subject string
+	if len(cert.URIs) > 0 {
+		subject = cert.URIs[0].String()
+	} else if len(cert.EmailAddresses) > 0 {
+		subject = cert.EmailAddresses[0]
+	} else {
+		return nil, fmt.Errorf("certificate has no URI or email SAN identifying the signer")
+	}
+
+	return &Identity{Subject: subject, Issuer: issuer}, nil
+}
+
+// checkIdentity confirms the verified identity matches the configured
+// allow-list, refusing otherwise-valid signatures from unexpected signers.
+func checkIdentity(identity *Identity, allowedIssuer, allowedIdentity string) error {
+	if allowedIssuer != "" && identity.Issuer != allowedIssuer {
+		return fmt.Errorf("signer issuer %q is not the expected %q", identity.Issuer, allowedIssuer)
+	}
+	if allowedIdentity != "" && identity.Subject != allowedIdentity {
+		return fmt.Errorf("signer identity %q is not the expected %q", identity.Subject, allowedIdentity)
+	}
+	return nil
+}
+
+func sha256File(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,295 @@
+// Package transparency verifies that a downloaded release artifact is
+// recorded in an append-only transparency log, using RFC 6962-style Merkle
+// inclusion proofs. It is deliberately independent of any particular log
+// implementation: callers supply a log base URL and get back a pass/fail
+// plus the signed tree head that was used, so the result can be cached and
+// checked for consistency on the next run.
+package transparency
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// InclusionProof is the Merkle audit path proving that LeafHash is present
+// in the tree described by TreeSize/RootHash.
+type InclusionProof struct {
+	LeafIndex int64    `json:"leaf_index"`
+	TreeSize  int64    `json:"tree_size"`
+	AuditPath [][]byte `json:"audit_path"`
+}
+
+// SignedTreeHead is the log's attestation of its own root at a point in time.
+type SignedTreeHead struct {
+	TreeSize  int64  `json:"tree_size"`
+	RootHash  []byte `json:"root_hash"`
+	Timestamp int64  `json:"timestamp"`
+	Signature []byte `json:"signature"`
+}
+
+type inclusionResponse struct {
+	LeafIndex int64    `json:"leaf_index"`
+	TreeSize  int64    `json:"tree_size"`
+	RootHash  string   `json:"root_hash"`
+	AuditPath []string `json:"audit_path"`
+	Timestamp int64    `json:"timestamp"`
+	Signature string   `json:"signature"`
+}
+
+// HashLeaf returns the RFC 6962 leaf hash: SHA-256(0x00 || data).
+func HashLeaf(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// hashChildren returns the RFC 6962 internal node hash: SHA-256(0x01 || left || right).
+func hashChildren(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// FetchInclusionProof requests a Merkle inclusion proof for artifactSHA256
+// (hex-encoded) from logURL and returns the proof alongside the signed tree
+// head it was issued against.
+func FetchInclusionProof(client *http.Client, logURL, artifactSHA256 string) (*InclusionProof, *SignedTreeHead, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	reqURL := fmt.Sprintf("%s/proof?hash=%s", logURL, artifactSHA256)
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build transparency log request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to reach transparency log %s: %w", logURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("transparency log returned status %d for %s", resp.StatusCode, artifactSHA256)
+	}
+
+	var parsed inclusionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode transparency log response: %w", err)
+	}
+
+	rootHash, err := base64.StdEncoding.DecodeString(parsed.RootHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid root hash in transparency log response: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(parsed.Signature)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid signature in transparency log response: %w", err)
+	}
+
+	auditPath := make([][]byte, 0, len(parsed.AuditPath))
+	for i, encoded := range parsed.AuditPath {
+		sibling, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid audit path entry %d in transparency log response: %w", i, err)
+		}
+		auditPath = append(auditPath, sibling)
+	}
+
+	proof := &InclusionProof{
+		LeafIndex: parsed.LeafIndex,
+		TreeSize:  parsed.TreeSize,
+		AuditPath: auditPath,
+	}
+	sth := &SignedTreeHead{
+		TreeSize:  parsed.TreeSize,
+		RootHash:  rootHash,
+		Timestamp: parsed.Timestamp,
+		Signature: signature,
+	}
+
+	return proof, sth, nil
+}
+
+type consistencyResponse struct {
+	ConsistencyPath []string `json:"consistency_path"`
+}
+
+// FetchConsistencyProof requests a consistency proof between tree sizes
+// first and second from logURL.
+func FetchConsistencyProof(client *http.Client, logURL string, first, second int64) ([][]byte, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	reqURL := fmt.Sprintf("%s/consistency?first=%d&second=%d", logURL, first, second)
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build consistency proof request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach transparency log %s: %w", logURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("transparency log returned status %d for consistency proof %d -> %d", resp.StatusCode, first, second)
+	}
+
+	var parsed consistencyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode consistency proof response: %w", err)
+	}
+
+	proof := make([][]byte, 0, len(parsed.ConsistencyPath))
+	for i, encoded := range parsed.ConsistencyPath {
+		node, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid consistency path entry %d in transparency log response: %w", i, err)
+		}
+		proof = append(proof, node)
+	}
+
+	return proof, nil
+}
+
+// VerifyInclusion recomputes the Merkle root from leafHash and proof, and
+// reports whether it matches sth.RootHash.
+func VerifyInclusion(leafHash []byte, proof InclusionProof, sth SignedTreeHead) (bool, error) {
+	if proof.TreeSize != sth.TreeSize {
+		return false, fmt.Errorf("proof tree size %d does not match signed tree head size %d", proof.TreeSize, sth.TreeSize)
+	}
+	if proof.LeafIndex < 0 || proof.LeafIndex >= proof.TreeSize {
+		return false, fmt.Errorf("leaf index %d out of range for tree size %d", proof.LeafIndex, proof.TreeSize)
+	}
+
+	computed := rootFromAuditPath(leafHash, proof.LeafIndex, proof.TreeSize, proof.AuditPath)
+	if computed == nil {
+		return false, fmt.Errorf("could not reconstruct root from audit path")
+	}
+
+	return string(computed) == string(sth.RootHash), nil
+}
+
+// rootFromAuditPath implements the standard RFC 6962 inclusion proof
+// verification algorithm: repeatedly combine the running hash with the next
+// sibling, choosing left/right order based on the bits of the leaf index,
+// then keep climbing past any node that's the root of a complete subtree
+// (no sibling of its own) before consuming the next audit path entry.
+func rootFromAuditPath(leafHash []byte, index, size int64, auditPath [][]byte) []byte {
+	node := leafHash
+	firstNode, lastNode := index, size-1
+
+	for _, sibling := range auditPath {
+		if firstNode%2 == 1 || firstNode == lastNode {
+			node = hashChildren(sibling, node)
+			for firstNode%2 == 0 && firstNode != 0 {
+				firstNode /= 2
+				lastNode /= 2
+			}
+		} else {
+			node = hashChildren(node, sibling)
+		}
+		firstNode /= 2
+		lastNode /= 2
+	}
+
+	return node
+}
+
+// VerifyConsistency checks that newSTH is a valid successor of oldSTH given a
+// consistency proof between the two tree sizes, so a client that has already
+// trusted oldSTH can detect a log that has forked or rewritten history. It
+// implements the RFC 6962 section 2.1.2 algorithm: reconstruct the root hash
+// at each tree size from the proof and require both to match the signed
+// tree heads supplied, rather than trusting TreeSize ordering alone.
+func VerifyConsistency(oldSTH, newSTH SignedTreeHead, proof [][]byte) (bool, error) {
+	first, second := oldSTH.TreeSize, newSTH.TreeSize
+	if second < first {
+		return false, fmt.Errorf("new tree size %d is smaller than last trusted size %d", second, first)
+	}
+	if first == 0 {
+		if len(proof) != 0 {
+			return false, fmt.Errorf("expected an empty consistency proof for tree size 0, got %d entries", len(proof))
+		}
+		return true, nil
+	}
+	if first == second {
+		if len(proof) != 0 {
+			return false, fmt.Errorf("expected an empty consistency proof for equal tree sizes, got %d entries", len(proof))
+		}
+		return string(oldSTH.RootHash) == string(newSTH.RootHash), nil
+	}
+	if len(proof) == 0 {
+		return false, fmt.Errorf("consistency proof required to move from tree size %d to %d", first, second)
+	}
+
+	// node/lastNode are 0-indexed node numbers at the current level for the
+	// rightmost node of the old tree and the new tree respectively; fr/sr
+	// are the running hashes reconstructing the old and new root.
+	node, lastNode := first-1, second-1
+	for node%2 == 1 {
+		node /= 2
+		lastNode /= 2
+	}
+
+	idx := 0
+	var fr, sr []byte
+	if node == 0 {
+		// first is an exact power of 2: its own root seeds both paths.
+		fr, sr = oldSTH.RootHash, oldSTH.RootHash
+	} else {
+		if idx >= len(proof) {
+			return false, fmt.Errorf("consistency proof is too short")
+		}
+		fr, sr = proof[idx], proof[idx]
+		idx++
+	}
+
+	for node > 0 {
+		if idx >= len(proof) {
+			return false, fmt.Errorf("consistency proof is too short")
+		}
+		switch {
+		case node%2 == 1:
+			fr = hashChildren(proof[idx], fr)
+			sr = hashChildren(proof[idx], sr)
+			idx++
+		case node < lastNode:
+			sr = hashChildren(sr, proof[idx])
+			idx++
+		}
+		node /= 2
+		lastNode /= 2
+	}
+	if string(fr) != string(oldSTH.RootHash) {
+		return false, fmt.Errorf("consistency proof does not reconstruct the trusted root at size %d", first)
+	}
+
+	for lastNode > 0 {
+		if idx >= len(proof) {
+			return false, fmt.Errorf("consistency proof is too short")
+		}
+		sr = hashChildren(sr, proof[idx])
+		idx++
+		lastNode /= 2
+	}
+	if idx != len(proof) {
+		return false, fmt.Errorf("consistency proof has unexpected trailing entries")
+	}
+	if string(sr) != string(newSTH.RootHash) {
+		return false, fmt.Errorf("consistency proof does not reconstruct the new root at size %d", second)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,170 @@
+package transparency
+
+import (
+	crand "crypto/rand"
+	"math/rand"
+	"testing"
+)
+
+// The tests below build reference Merkle trees and proofs directly from
+// the RFC 6962 algorithms (rather than reusing this package's own
+// functions to generate them) so they can catch a broken VerifyInclusion
+// or VerifyConsistency instead of just confirming self-consistency.
+
+func mth(leaves [][]byte) []byte {
+	n := len(leaves)
+	if n == 1 {
+		return HashLeaf(leaves[0])
+	}
+	k := largestPowerOfTwoLessThan(n)
+	return hashChildren(mth(leaves[:k]), mth(leaves[k:]))
+}
+
+func largestPowerOfTwoLessThan(n int) int {
+	k := 1
+	for k*2 < n {
+		k *= 2
+	}
+	return k
+}
+
+// inclusionPath mirrors RFC 6962's PATH(m, D[n]) definition: the audit path
+// proving leaf m is included in the tree over leaves D[n].
+func inclusionPath(m int, leaves [][]byte) [][]byte {
+	n := len(leaves)
+	if n == 1 {
+		return nil
+	}
+	k := largestPowerOfTwoLessThan(n)
+	if m < k {
+		return append(inclusionPath(m, leaves[:k]), mth(leaves[k:]))
+	}
+	return append(inclusionPath(m-k, leaves[k:]), mth(leaves[:k]))
+}
+
+// subproof mirrors RFC 6962's SUBPROOF(m, D[n], b) definition, the building
+// block of a consistency proof between sizes m and len(leaves).
+func subproof(m int, leaves [][]byte, b bool) [][]byte {
+	n := len(leaves)
+	if m == n {
+		if b {
+			return nil
+		}
+		return [][]byte{mth(leaves)}
+	}
+	k := largestPowerOfTwoLessThan(n)
+	if m <= k {
+		return append(subproof(m, leaves[:k], b), mth(leaves[k:]))
+	}
+	return append(subproof(m-k, leaves[k:], false), mth(leaves[:k]))
+}
+
+func consistencyProof(m int, leaves [][]byte) [][]byte {
+	return subproof(m, leaves, true)
+}
+
+func randomLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		b := make([]byte, 16)
+		crand.Read(b)
+		leaves[i] = b
+	}
+	return leaves
+}
+
+func TestVerifyInclusion_RoundTrip(t *testing.T) {
+	for trial := 0; trial < 100; trial++ {
+		n := rand.Intn(64) + 1
+		leaves := randomLeaves(n)
+		idx := rand.Intn(n)
+
+		root := mth(leaves)
+		path := inclusionPath(idx, leaves)
+
+		proof := InclusionProof{LeafIndex: int64(idx), TreeSize: int64(n), AuditPath: path}
+		sth := SignedTreeHead{TreeSize: int64(n), RootHash: root}
+
+		ok, err := VerifyInclusion(HashLeaf(leaves[idx]), proof, sth)
+		if err != nil || !ok {
+			t.Fatalf("n=%d idx=%d: VerifyInclusion failed: ok=%v err=%v", n, idx, ok, err)
+		}
+
+		wrongLeaf := HashLeaf(randomLeaves(1)[0])
+		if ok, _ := VerifyInclusion(wrongLeaf, proof, sth); ok {
+			t.Fatalf("n=%d idx=%d: expected failure verifying the wrong leaf", n, idx)
+		}
+	}
+}
+
+func TestVerifyConsistency_RoundTrip(t *testing.T) {
+	for trial := 0; trial < 100; trial++ {
+		n := rand.Intn(64) + 2
+		leaves := randomLeaves(n)
+		m := rand.Intn(n-1) + 1 // 1 <= m < n
+
+		oldSTH := SignedTreeHead{TreeSize: int64(m), RootHash: mth(leaves[:m])}
+		newSTH := SignedTreeHead{TreeSize: int64(n), RootHash: mth(leaves)}
+		proof := consistencyProof(m, leaves)
+
+		ok, err := VerifyConsistency(oldSTH, newSTH, proof)
+		if err != nil || !ok {
+			t.Fatalf("n=%d m=%d: VerifyConsistency failed: ok=%v err=%v", n, m, ok, err)
+		}
+	}
+}
+
+func TestVerifyConsistency_RejectsEqualSizeMismatch(t *testing.T) {
+	a := SignedTreeHead{TreeSize: 10, RootHash: []byte("root-a")}
+	b := SignedTreeHead{TreeSize: 10, RootHash: []byte("root-b")}
+	ok, err := VerifyConsistency(a, b, nil)
+	if ok || err != nil {
+		t.Fatalf("expected ok=false, err=nil for mismatched equal-size roots, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestVerifyConsistency_RejectsShrinkingTree(t *testing.T) {
+	oldSTH := SignedTreeHead{TreeSize: 10, RootHash: []byte("root")}
+	newSTH := SignedTreeHead{TreeSize: 5, RootHash: []byte("root2")}
+	if ok, err := VerifyConsistency(oldSTH, newSTH, nil); ok || err == nil {
+		t.Fatalf("expected rejection of a shrinking tree, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestVerifyConsistency_RejectsForkedHistory(t *testing.T) {
+	leavesA := randomLeaves(8)
+	leavesB := append([][]byte{}, leavesA...)
+	leavesB[3] = append([]byte{}, leavesA[3]...)
+	leavesB[3][0] ^= 0x01 // force the fork to diverge from leavesA
+
+	oldSTH := SignedTreeHead{TreeSize: 8, RootHash: mth(leavesA)}
+
+	forked := append(append([][]byte{}, leavesB...), randomLeaves(4)...)
+	newSTH := SignedTreeHead{TreeSize: int64(len(forked)), RootHash: mth(forked)}
+	proof := consistencyProof(8, forked)
+
+	if ok, _ := VerifyConsistency(oldSTH, newSTH, proof); ok {
+		t.Fatal("expected rejection of a forked/rewritten history")
+	}
+}
+
+func TestVerifyConsistency_TamperedProofEntryRejected(t *testing.T) {
+	n, m := 20, 7
+	leaves := randomLeaves(n)
+	oldSTH := SignedTreeHead{TreeSize: int64(m), RootHash: mth(leaves[:m])}
+	newSTH := SignedTreeHead{TreeSize: int64(n), RootHash: mth(leaves)}
+	proof := consistencyProof(m, leaves)
+	if len(proof) == 0 {
+		t.Fatal("expected a non-empty proof for this tree shape")
+	}
+
+	tampered := make([][]byte, len(proof))
+	for i, p := range proof {
+		tampered[i] = append([]byte{}, p...)
+	}
+	tampered[0][0] ^= 0xFF
+
+	if ok, _ := VerifyConsistency(oldSTH, newSTH, tampered); ok {
+		t.Fatal("expected rejection of a tampered consistency proof entry")
+	}
+}
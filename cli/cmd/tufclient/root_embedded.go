@@ -0,0 +1,10 @@
+package tufclient
+
+import _ "embed"
+
+// embeddedRootJSON is the trusted root of trust shipped with this build, the
+// TUF root-of-trust's only out-of-band distribution point. A new signing
+// key hierarchy requires a new `lf` release with an updated root.json here.
+//
+//go:embed root.json
+var embeddedRootJSON []byte
@@ -0,0 +1,434 @@
+// Package tufclient implements a minimal Update Framework (TUF) client for
+// verifying `lf` release binaries: it carries the embedded root of trust,
+// walks the timestamp -> snapshot -> targets metadata chain (checking
+// expiration and per-role signature thresholds), and verifies a downloaded
+// target's length and SHA256 against the signed targets metadata before the
+// caller installs it. This lets `lf version upgrade` survive a compromised
+// release server, since a single leaked signing key for snapshot or targets
+// isn't enough to serve a malicious binary without detection.
+package tufclient
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Key is a single TUF public key entry from root.json.
+type Key struct {
+	KeyType string `json:"keytype"`
+	KeyVal  struct {
+		Public string `json:"public"` // hex-encoded
+	} `json:"keyval"`
+}
+
+// RoleKeys lists which keys sign a role and how many valid signatures are
+// required to trust that role's metadata.
+type RoleKeys struct {
+	KeyIDs    []string `json:"keyids"`
+	Threshold int      `json:"threshold"`
+}
+
+// RootSigned is the signed portion of root.json.
+type RootSigned struct {
+	Type    string              `json:"_type"`
+	Version int                 `json:"version"`
+	Expires time.Time           `json:"expires"`
+	Keys    map[string]Key      `json:"keys"`
+	Roles   map[string]RoleKeys `json:"roles"`
+}
+
+// FileMeta describes a metadata file referenced by timestamp.json or
+// snapshot.json: which version of it is current.
+type FileMeta struct {
+	Version int `json:"version"`
+}
+
+// TimestampSigned is the signed portion of timestamp.json.
+type TimestampSigned struct {
+	Type    string              `json:"_type"`
+	Version int                 `json:"version"`
+	Expires time.Time           `json:"expires"`
+	Meta    map[string]FileMeta `json:"meta"`
+}
+
+// SnapshotSigned is the signed portion of snapshot.json.
+type SnapshotSigned struct {
+	Type    string              `json:"_type"`
+	Version int                 `json:"version"`
+	Expires time.Time           `json:"expires"`
+	Meta    map[string]FileMeta `json:"meta"`
+}
+
+// TargetFileMeta describes one downloadable target: its length and content
+// hashes, which the client must verify before trusting the download.
+//
+// Hashes["sha256"] is always the hash of the target file exactly as served
+// (for release binaries, the gzip-compressed asset) and is what
+// Client.DownloadTarget checks. A release binary target may additionally
+// carry Hashes["sha256-uncompressed"], the hash of its decompressed
+// content - this lets a delta-reconstructed binary (which never goes
+// through a gzip round trip) be verified against the same target entry.
+type TargetFileMeta struct {
+	Length int64             `json:"length"`
+	Hashes map[string]string `json:"hashes"`
+}
+
+// TargetsSigned is the signed portion of targets.json.
+type TargetsSigned struct {
+	Type    string                    `json:"_type"`
+	Version int                       `json:"version"`
+	Expires time.Time                 `json:"expires"`
+	Targets map[string]TargetFileMeta `json:"targets"`
+}
+
+// Signature is one signature over a metadata file's "signed" field.
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"` // hex-encoded
+}
+
+// Envelope is the on-the-wire shape of every TUF metadata file: a signed
+// payload plus the signatures over it.
+type Envelope struct {
+	Signed     json.RawMessage `json:"signed"`
+	Signatures []Signature     `json:"signatures"`
+}
+
+// Client fetches and verifies TUF metadata and targets from a TUF repository.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	trustDir   string
+	root       RootSigned
+	rootKeys   map[string]ed25519.PublicKey
+}
+
+// New creates a Client for the TUF repository at baseURL, trusting the
+// embedded root.json shipped with this build. Trusted metadata from prior
+// updates is persisted under $HOME/.llamafarm/tuf/ for rollback protection.
+func New(baseURL string) (*Client, error) {
+	var env Envelope
+	if err := json.Unmarshal(embeddedRootJSON, &env); err != nil {
+		return nil, fmt.Errorf("embedded root.json is invalid: %w", err)
+	}
+
+	var root RootSigned
+	if err := json.Unmarshal(env.Signed, &root); err != nil {
+		return nil, fmt.Errorf("embedded root.json signed payload is invalid: %w", err)
+	}
+
+	keys, err := parseKeys(root.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("embedded root.json has an invalid key: %w", err)
+	}
+
+	if err := verifyThreshold(env, root.Roles["root"], keys); err != nil {
+		return nil, fmt.Errorf("embedded root.json failed self-verification: %w", err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine home directory: %w", err)
+	}
+
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		trustDir:   filepath.Join(home, ".llamafarm", "tuf"),
+		root:       root,
+		rootKeys:   keys,
+	}, nil
+}
+
+func parseKeys(keys map[string]Key) (map[string]ed25519.PublicKey, error) {
+	out := make(map[string]ed25519.PublicKey, len(keys))
+	for id, k := range keys {
+		raw, err := hex.DecodeString(k.KeyVal.Public)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("key %s is not a valid ed25519 public key", id)
+		}
+		out[id] = ed25519.PublicKey(raw)
+	}
+	return out, nil
+}
+
+// verifyThreshold checks that at least role.Threshold signatures over
+// env.Signed are valid and come from distinct keys listed in role.KeyIDs.
+func verifyThreshold(env Envelope, role RoleKeys, keys map[string]ed25519.PublicKey) error {
+	allowed := make(map[string]bool, len(role.KeyIDs))
+	for _, id := range role.KeyIDs {
+		allowed[id] = true
+	}
+
+	valid := 0
+	seen := make(map[string]bool)
+	for _, sig := range env.Signatures {
+		if seen[sig.KeyID] || !allowed[sig.KeyID] {
+			continue
+		}
+		pub, ok := keys[sig.KeyID]
+		if !ok {
+			continue
+		}
+		sigBytes, err := hex.DecodeString(sig.Sig)
+		if err != nil {
+			continue
+		}
+		if ed25519.Verify(pub, env.Signed, sigBytes) {
+			valid++
+			seen[sig.KeyID] = true
+		}
+	}
+
+	if valid < role.Threshold {
+		return fmt.Errorf("got %d valid signature(s), need %d", valid, role.Threshold)
+	}
+	return nil
+}
+
+// Update walks the timestamp -> snapshot -> targets chain, verifying
+// expiration, signature thresholds, and that each file's version matches
+// what the file above it in the chain expects. It rejects any file whose
+// version is older than the last trusted one on disk (rollback protection),
+// then persists the newly trusted metadata.
+func (c *Client) Update(ctx context.Context) (*TargetsSigned, error) {
+	timestampEnv, timestamp, err := c.fetchAndVerify(ctx, "timestamp.json", c.root.Roles["timestamp"])
+	if err != nil {
+		return nil, fmt.Errorf("timestamp: %w", err)
+	}
+	var ts TimestampSigned
+	if err := json.Unmarshal(timestamp, &ts); err != nil {
+		return nil, fmt.Errorf("timestamp: invalid metadata: %w", err)
+	}
+	if err := checkExpiry(ts.Expires); err != nil {
+		return nil, fmt.Errorf("timestamp: %w", err)
+	}
+	if err := c.checkRollback("timestamp.json", ts.Version); err != nil {
+		return nil, err
+	}
+
+	snapshotMeta, ok := ts.Meta["snapshot.json"]
+	if !ok {
+		return nil, fmt.Errorf("timestamp: missing snapshot.json entry")
+	}
+
+	snapshotEnv, snapshot, err := c.fetchAndVerify(ctx, "snapshot.json", c.root.Roles["snapshot"])
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: %w", err)
+	}
+	var snap SnapshotSigned
+	if err := json.Unmarshal(snapshot, &snap); err != nil {
+		return nil, fmt.Errorf("snapshot: invalid metadata: %w", err)
+	}
+	if snap.Version != snapshotMeta.Version {
+		return nil, fmt.Errorf("snapshot: version %d does not match timestamp's expected version %d", snap.Version, snapshotMeta.Version)
+	}
+	if err := checkExpiry(snap.Expires); err != nil {
+		return nil, fmt.Errorf("snapshot: %w", err)
+	}
+	if err := c.checkRollback("snapshot.json", snap.Version); err != nil {
+		return nil, err
+	}
+
+	targetsMeta, ok := snap.Meta["targets.json"]
+	if !ok {
+		return nil, fmt.Errorf("snapshot: missing targets.json entry")
+	}
+
+	targetsEnv, targets, err := c.fetchAndVerify(ctx, "targets.json", c.root.Roles["targets"])
+	if err != nil {
+		return nil, fmt.Errorf("targets: %w", err)
+	}
+	var tgt TargetsSigned
+	if err := json.Unmarshal(targets, &tgt); err != nil {
+		return nil, fmt.Errorf("targets: invalid metadata: %w", err)
+	}
+	if tgt.Version != targetsMeta.Version {
+		return nil, fmt.Errorf("targets: version %d does not match snapshot's expected version %d", tgt.Version, targetsMeta.Version)
+	}
+	if err := checkExpiry(tgt.Expires); err != nil {
+		return nil, fmt.Errorf("targets: %w", err)
+	}
+	if err := c.checkRollback("targets.json", tgt.Version); err != nil {
+		return nil, err
+	}
+
+	if err := c.persistTrusted("timestamp.json", timestampEnv, ts.Version); err != nil {
+		return nil, err
+	}
+	if err := c.persistTrusted("snapshot.json", snapshotEnv, snap.Version); err != nil {
+		return nil, err
+	}
+	if err := c.persistTrusted("targets.json", targetsEnv, tgt.Version); err != nil {
+		return nil, err
+	}
+
+	return &tgt, nil
+}
+
+// fetchAndVerify downloads name from the repository and checks its
+// signatures against role, returning both the raw envelope (for persisting)
+// and the signed payload (for parsing).
+func (c *Client) fetchAndVerify(ctx context.Context, name string, role RoleKeys) ([]byte, json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+name, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, name)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, nil, fmt.Errorf("%s is not valid metadata: %w", name, err)
+	}
+
+	if err := verifyThreshold(env, role, c.rootKeys); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return body, env.Signed, nil
+}
+
+func checkExpiry(expires time.Time) error {
+	if time.Now().After(expires) {
+		return fmt.Errorf("metadata expired at %s", expires)
+	}
+	return nil
+}
+
+// checkRollback rejects name if its version is older than the last trusted
+// version we persisted, defending against a compromised server replaying
+// stale (but validly signed) metadata.
+func (c *Client) checkRollback(name string, version int) error {
+	path := filepath.Join(c.trustDir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read trusted %s: %w", name, err)
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		// A corrupt local trust file shouldn't block upgrades; the fetched
+		// metadata is independently signature- and expiry-checked.
+		return nil
+	}
+
+	var prev struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(env.Signed, &prev); err != nil {
+		return nil
+	}
+
+	if version < prev.Version {
+		return fmt.Errorf("%s: refusing rollback from trusted version %d to %d", name, prev.Version, version)
+	}
+	return nil
+}
+
+func (c *Client) persistTrusted(name string, raw []byte, version int) error {
+	if err := os.MkdirAll(c.trustDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create TUF trust directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(c.trustDir, name), raw, 0o644); err != nil {
+		return fmt.Errorf("failed to persist trusted %s (version %d): %w", name, version, err)
+	}
+	return nil
+}
+
+// ResolveTargetPath returns the TUF target path for a release binary, e.g.
+// "/lf-v1.2.3-linux-amd64.gz".
+func ResolveTargetPath(version, goos, goarch string) string {
+	return fmt.Sprintf("/lf-%s-%s-%s.gz", version, goos, goarch)
+}
+
+// ResolveDeltaTargetPath returns the TUF target path for a binary delta
+// patch between two releases, e.g. "/lf-v1.2.2-v1.2.3-linux-amd64.bsdiff".
+// Not every release publishes a delta for every prior version, so callers
+// should treat a missing target (DownloadTarget's "not a known TUF target"
+// error) as "no delta available" rather than a hard failure.
+func ResolveDeltaTargetPath(fromVersion, toVersion, goos, goarch string) string {
+	return fmt.Sprintf("/lf-%s-%s-%s-%s.bsdiff", fromVersion, toVersion, goos, goarch)
+}
+
+// DownloadTarget downloads targetPath from the repository to destPath,
+// verifying its length and SHA256 against targets before returning. The
+// partially-written file is removed on any verification failure.
+func (c *Client) DownloadTarget(ctx context.Context, targets *TargetsSigned, targetPath, destPath string) error {
+	meta, ok := targets.Targets[targetPath]
+	if !ok {
+		return fmt.Errorf("%s is not a known TUF target", targetPath)
+	}
+	wantSHA256, ok := meta.Hashes["sha256"]
+	if !ok {
+		return fmt.Errorf("%s has no sha256 hash in TUF metadata", targetPath)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+targetPath, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d downloading %s", resp.StatusCode, targetPath)
+	}
+
+	out, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", destPath, err)
+	}
+
+	hasher := sha256.New()
+	length, err := io.Copy(out, io.TeeReader(resp.Body, hasher))
+	closeErr := out.Close()
+	if err != nil {
+		os.Remove(destPath)
+		return fmt.Errorf("failed to download %s: %w", targetPath, err)
+	}
+	if closeErr != nil {
+		os.Remove(destPath)
+		return fmt.Errorf("failed to finalize %s: %w", destPath, closeErr)
+	}
+
+	if length != meta.Length {
+		os.Remove(destPath)
+		return fmt.Errorf("%s: downloaded %d bytes, expected %d", targetPath, length, meta.Length)
+	}
+	if gotSHA256 := hex.EncodeToString(hasher.Sum(nil)); gotSHA256 != wantSHA256 {
+		os.Remove(destPath)
+		return fmt.Errorf("%s: sha256 mismatch: got %s, want %s", targetPath, gotSHA256, wantSHA256)
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package tufclient
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNew_VerifiesEmbeddedRoot(t *testing.T) {
+	client, err := New("https://example.invalid")
+	if err != nil {
+		t.Fatalf("New failed to self-verify the embedded root.json: %v", err)
+	}
+	if len(client.rootKeys) == 0 {
+		t.Fatal("expected at least one parsed root key")
+	}
+}
+
+func newTestKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate test key: %v", err)
+	}
+	return pub, priv
+}
+
+func sign(priv ed25519.PrivateKey, keyID string, payload []byte) Envelope {
+	sig := ed25519.Sign(priv, payload)
+	return Envelope{
+		Signed:     payload,
+		Signatures: []Signature{{KeyID: keyID, Sig: hex.EncodeToString(sig)}},
+	}
+}
+
+func TestVerifyThreshold(t *testing.T) {
+	pub, priv := newTestKey(t)
+	keys := map[string]ed25519.PublicKey{"key-1": pub}
+	role := RoleKeys{KeyIDs: []string{"key-1"}, Threshold: 1}
+	payload := []byte(`{"version":1}`)
+
+	t.Run("valid signature passes", func(t *testing.T) {
+		env := sign(priv, "key-1", payload)
+		if err := verifyThreshold(env, role, keys); err != nil {
+			t.Fatalf("expected valid signature to pass, got: %v", err)
+		}
+	})
+
+	t.Run("signature from the wrong key fails", func(t *testing.T) {
+		_, otherPriv := newTestKey(t)
+		env := sign(otherPriv, "key-1", payload)
+		if err := verifyThreshold(env, role, keys); err == nil {
+			t.Fatal("expected a signature from the wrong key to fail verification")
+		}
+	})
+
+	t.Run("signature over different payload fails", func(t *testing.T) {
+		env := sign(priv, "key-1", payload)
+		env.Signed = []byte(`{"version":2}`)
+		if err := verifyThreshold(env, role, keys); err == nil {
+			t.Fatal("expected a signature over a different payload to fail")
+		}
+	})
+
+	t.Run("below threshold fails", func(t *testing.T) {
+		env := sign(priv, "key-1", payload)
+		twoOfTwo := RoleKeys{KeyIDs: []string{"key-1", "key-2"}, Threshold: 2}
+		if err := verifyThreshold(env, twoOfTwo, keys); err == nil {
+			t.Fatal("expected a single signature to fail a threshold-2 role")
+		}
+	})
+
+	t.Run("duplicate signatures from the same key don't count twice", func(t *testing.T) {
+		env := sign(priv, "key-1", payload)
+		env.Signatures = append(env.Signatures, env.Signatures[0])
+		twoOfOne := RoleKeys{KeyIDs: []string{"key-1"}, Threshold: 2}
+		if err := verifyThreshold(env, twoOfOne, keys); err == nil {
+			t.Fatal("expected two copies of the same key's signature not to satisfy a threshold of 2")
+		}
+	})
+}
+
+func TestCheckExpiry(t *testing.T) {
+	if err := checkExpiry(time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("expected a future expiry to pass, got: %v", err)
+	}
+	if err := checkExpiry(time.Now().Add(-time.Hour)); err == nil {
+		t.Fatal("expected a past expiry to fail")
+	}
+}
+
+func TestCheckRollback(t *testing.T) {
+	dir := t.TempDir()
+	c := &Client{trustDir: dir}
+
+	if err := c.checkRollback("snapshot.json", 5); err != nil {
+		t.Fatalf("expected no rollback error with nothing trusted yet, got: %v", err)
+	}
+
+	if err := c.persistTrusted("snapshot.json", []byte(`{"signed":{"version":5},"signatures":[]}`), 5); err != nil {
+		t.Fatalf("persistTrusted failed: %v", err)
+	}
+
+	if err := c.checkRollback("snapshot.json", 5); err != nil {
+		t.Fatalf("expected same version to be allowed, got: %v", err)
+	}
+	if err := c.checkRollback("snapshot.json", 6); err != nil {
+		t.Fatalf("expected a newer version to be allowed, got: %v", err)
+	}
+	if err := c.checkRollback("snapshot.json", 4); err == nil {
+		t.Fatal("expected an older version to be rejected as a rollback")
+	}
+}
+
+func TestDownloadTarget(t *testing.T) {
+	content := []byte("pretend this is an lf binary")
+	sum := sha256.Sum256(content)
+	sha256Hex := hex.EncodeToString(sum[:])
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer srv.Close()
+
+	c := &Client{baseURL: srv.URL, httpClient: srv.Client()}
+	targets := &TargetsSigned{Targets: map[string]TargetFileMeta{
+		"/lf-v1.0.0-linux-amd64.gz": {
+			Length: int64(len(content)),
+			Hashes: map[string]string{"sha256": sha256Hex},
+		},
+	}}
+
+	t.Run("succeeds and verifies hash", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "out")
+		if err := c.DownloadTarget(context.Background(), targets, "/lf-v1.0.0-linux-amd64.gz", dest); err != nil {
+			t.Fatalf("DownloadTarget failed: %v", err)
+		}
+		got, err := os.ReadFile(dest)
+		if err != nil {
+			t.Fatalf("failed to read downloaded file: %v", err)
+		}
+		if string(got) != string(content) {
+			t.Fatalf("downloaded content mismatch: got %q want %q", got, content)
+		}
+	})
+
+	t.Run("unknown target path is rejected", func(t *testing.T) {
+		dest := filepath.Join(t.TempDir(), "out")
+		if err := c.DownloadTarget(context.Background(), targets, "/does-not-exist.gz", dest); err == nil {
+			t.Fatal("expected an unknown target path to fail")
+		}
+	})
+
+	t.Run("length mismatch is rejected and file is removed", func(t *testing.T) {
+		bad := &TargetsSigned{Targets: map[string]TargetFileMeta{
+			"/lf-v1.0.0-linux-amd64.gz": {Length: int64(len(content)) + 1, Hashes: map[string]string{"sha256": sha256Hex}},
+		}}
+		dest := filepath.Join(t.TempDir(), "out")
+		if err := c.DownloadTarget(context.Background(), bad, "/lf-v1.0.0-linux-amd64.gz", dest); err == nil {
+			t.Fatal("expected a length mismatch to fail")
+		}
+		if _, err := os.Stat(dest); !os.IsNotExist(err) {
+			t.Fatal("expected the partially-written file to be removed on verification failure")
+		}
+	})
+
+	t.Run("hash mismatch is rejected and file is removed", func(t *testing.T) {
+		bad := &TargetsSigned{Targets: map[string]TargetFileMeta{
+			"/lf-v1.0.0-linux-amd64.gz": {Length: int64(len(content)), Hashes: map[string]string{"sha256": "0000000000000000000000000000000000000000000000000000000000000000"}},
+		}}
+		dest := filepath.Join(t.TempDir(), "out")
+		if err := c.DownloadTarget(context.Background(), bad, "/lf-v1.0.0-linux-amd64.gz", dest); err == nil {
+			t.Fatal("expected a sha256 mismatch to fail")
+		}
+		if _, err := os.Stat(dest); !os.IsNotExist(err) {
+			t.Fatal("expected the partially-written file to be removed on verification failure")
+		}
+	})
+}
+
+func TestResolveTargetPaths(t *testing.T) {
+	if got, want := ResolveTargetPath("v1.2.3", "linux", "amd64"), "/lf-v1.2.3-linux-amd64.gz"; got != want {
+		t.Fatalf("ResolveTargetPath: got %q want %q", got, want)
+	}
+	if got, want := ResolveDeltaTargetPath("v1.2.2", "v1.2.3", "linux", "amd64"), "/lf-v1.2.2-v1.2.3-linux-amd64.bsdiff"; got != want {
+		t.Fatalf("ResolveDeltaTargetPath: got %q want %q", got, want)
+	}
+}
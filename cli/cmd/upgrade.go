@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// lfUpgradeCmd is the top-level `lf upgrade` shortcut for `lf version upgrade`.
+// It exists alongside the nested command so `lf upgrade` behaves the way
+// users of kubectl/helm expect a self-updater to behave: a first-class verb
+// at the root of the CLI rather than something buried under `version`.
+var lfUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [version]",
+	Short: "Download and install the latest LlamaFarm CLI release",
+	Long: `Download and install a new LlamaFarm CLI release in place.
+
+This is equivalent to 'lf version upgrade' and is provided as a top-level
+command since upgrading is a common, first-class operation.
+
+Examples:
+  lf upgrade                  # Upgrade to the latest version
+  lf upgrade v1.2.3           # Upgrade to a specific version
+  lf upgrade --check-only     # Report whether an upgrade is available and exit
+  lf upgrade --dry-run        # Show what would be done
+  lf upgrade --force          # Reinstall even if already on the target version`,
+	RunE: runUpgrade,
+}
+
+func init() {
+	lfUpgradeCmd.Flags().Bool("dry-run", false, "Show upgrade plan without executing")
+	lfUpgradeCmd.Flags().Bool("force", false, "Force upgrade even if same version")
+	lfUpgradeCmd.Flags().Bool("no-verify", false, "Skip TUF and checksum verification entirely (DANGEROUS: disables all protection against a compromised release server)")
+	lfUpgradeCmd.Flags().String("install-dir", "", "Override installation directory")
+	lfUpgradeCmd.Flags().Bool("check-only", false, "Only report whether a newer release is available, without installing it")
+
+	rootCmd.AddCommand(lfUpgradeCmd)
+}
+
+// runUpgrade is the shared RunE for both `lf upgrade` and `lf version
+// upgrade`, so the two commands can't drift on what --check-only does.
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	checkOnly, _ := cmd.Flags().GetBool("check-only")
+	if checkOnly {
+		return runCheckOnly(cmd, args)
+	}
+	return performUpgrade(cmd, args)
+}
+
+// runCheckOnly reports on available upgrades without downloading or installing
+// anything. It honors the same version resolution as performUpgrade so the
+// reported target matches what an actual upgrade would install.
+func runCheckOnly(cmd *cobra.Command, args []string) error {
+	noVerify, _ := cmd.Flags().GetBool("no-verify")
+	targetVersion, info, err := determineTargetVersion(args, noVerify)
+	if err != nil {
+		return err
+	}
+
+	if !info.UpdateAvailable && targetVersion == info.CurrentVersionNormalized {
+		fmt.Printf("✅ Already running version %s\n", info.CurrentVersion)
+		return nil
+	}
+
+	fmt.Printf("📦 Update available: %s -> %s\n", info.CurrentVersion, info.LatestVersion)
+	if info.ReleaseURL != "" {
+		fmt.Printf("   Release notes: %s\n", info.ReleaseURL)
+	}
+	if info.ReleaseNotes != "" {
+		fmt.Printf("\n%s\n", info.ReleaseNotes)
+	}
+	fmt.Printf("\nRun 'lf upgrade' to install.\n")
+	return nil
+}
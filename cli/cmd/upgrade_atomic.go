@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultKeepBackups is how many previous binary backups `lf version
+// upgrade` retains by default, so `lf version rollback` has somewhere to go
+// back to without backups accumulating forever.
+const defaultKeepBackups = 3
+
+// selfcheckTimeout bounds how long the post-install `lf __selfcheck` spawn
+// is given before the upgrade is treated as failed and rolled back.
+const selfcheckTimeout = 5 * time.Second
+
+func init() {
+	upgradeCmd.Flags().Int("keep-backups", defaultKeepBackups, "Number of previous binary backups to retain for rollback")
+	lfUpgradeCmd.Flags().Int("keep-backups", defaultKeepBackups, "Number of previous binary backups to retain for rollback")
+}
+
+// backupPath returns the path an upgrade backup of finalBinaryPath at
+// version is stored at.
+func backupPath(finalBinaryPath, version string) string {
+	return fmt.Sprintf("%s.bak-%s", finalBinaryPath, version)
+}
+
+// performAtomicUpgrade swaps newBinary into finalBinaryPath, keeping the
+// previously installed binary as a same-directory backup. After the swap it
+// runs `lf __selfcheck` against the new binary; if the binary fails to
+// start, times out, or fails validateBinaryPath, the backup is renamed back
+// into place and an error is returned rather than leaving a broken install.
+func performAtomicUpgrade(finalBinaryPath, newBinary, previousVersion string, keepBackups int) error {
+	bak := backupPath(finalBinaryPath, previousVersion)
+
+	if _, err := os.Stat(finalBinaryPath); err == nil {
+		if err := renameRunningBinary(finalBinaryPath, bak); err != nil {
+			return fmt.Errorf("failed to back up current binary: %w", err)
+		}
+	}
+
+	staged, err := stageInSameDir(finalBinaryPath, newBinary)
+	if err != nil {
+		restoreBackup(bak, finalBinaryPath)
+		return fmt.Errorf("failed to stage new binary: %w", err)
+	}
+
+	if err := os.Rename(staged, finalBinaryPath); err != nil {
+		os.Remove(staged)
+		restoreBackup(bak, finalBinaryPath)
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+
+	if err := runSelfcheck(finalBinaryPath); err != nil {
+		if restoreErr := restoreBackup(bak, finalBinaryPath); restoreErr != nil {
+			return fmt.Errorf("new binary failed self-check (%v) and rollback also failed: %w", err, restoreErr)
+		}
+		return fmt.Errorf("new binary failed self-check, rolled back to previous version: %w", err)
+	}
+
+	if err := pruneOldBackups(finalBinaryPath, keepBackups); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to prune old backups: %v\n", err)
+	}
+
+	return nil
+}
+
+// windowsRenameRetries and windowsRenameBackoff bound how long
+// renameRunningBinary retries on Windows before giving up.
+const (
+	windowsRenameRetries = 10
+	windowsRenameBackoff = 200 * time.Millisecond
+)
+
+// renameRunningBinary moves finalBinaryPath (the binary the currently
+// executing process was loaded from, in the self-upgrade case) to bak.
+// Unlike Unix, Windows can briefly keep a just-exited child process's image
+// file (here, the previous `lf __selfcheck` invocation, or an AV scan
+// reacting to the new file landing on disk) locked against rename for a few
+// hundred milliseconds after it exits, so a single os.Rename attempt is
+// flaky there in a way it never is on Unix. Retry with a short backoff on
+// Windows; elsewhere, preserve the original fail-fast behavior.
+func renameRunningBinary(finalBinaryPath, bak string) error {
+	if runtime.GOOS != "windows" {
+		return os.Rename(finalBinaryPath, bak)
+	}
+
+	var err error
+	for attempt := 0; attempt < windowsRenameRetries; attempt++ {
+		if err = os.Rename(finalBinaryPath, bak); err == nil {
+			return nil
+		}
+		time.Sleep(windowsRenameBackoff)
+	}
+	return err
+}
+
+// stageInSameDir copies src into a hidden temp file in finalBinaryPath's
+// directory, so the install rename that follows is an atomic same-filesystem
+// rename even when src was downloaded into the system temp directory.
+func stageInSameDir(finalBinaryPath, src string) (string, error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", err
+	}
+
+	staged, err := os.CreateTemp(filepath.Dir(finalBinaryPath), ".lf-upgrade-*")
+	if err != nil {
+		return "", err
+	}
+	defer staged.Close()
+
+	if _, err := staged.Write(data); err != nil {
+		os.Remove(staged.Name())
+		return "", err
+	}
+	if err := os.Chmod(staged.Name(), 0755); err != nil {
+		os.Remove(staged.Name())
+		return "", err
+	}
+
+	return staged.Name(), nil
+}
+
+// restoreBackup atomically renames bak back into place at finalBinaryPath.
+// It is a no-op if bak doesn't exist, e.g. a fresh install with no prior
+// binary to back up.
+func restoreBackup(bak, finalBinaryPath string) error {
+	if _, err := os.Stat(bak); err != nil {
+		return nil
+	}
+	return os.Rename(bak, finalBinaryPath)
+}
+
+// runSelfcheck spawns binaryPath __selfcheck and confirms it starts up and
+// exits 0 within selfcheckTimeout, catching a corrupt or broken build before
+// the user's next invocation does.
+func runSelfcheck(binaryPath string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), selfcheckTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, binaryPath, "__selfcheck")
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("self-check timed out after %s", selfcheckTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("self-check failed: %w (output: %s)", err, strings.TrimSpace(string(output)))
+	}
+
+	if err := validateBinaryPath(binaryPath); err != nil {
+		return fmt.Errorf("self-check passed but binary failed validation: %w", err)
+	}
+	return nil
+}
+
+// pruneOldBackups removes all but the keep most recently created .bak-*
+// backups for finalBinaryPath.
+func pruneOldBackups(finalBinaryPath string, keep int) error {
+	backups, err := listBackups(finalBinaryPath)
+	if err != nil {
+		return err
+	}
+	if len(backups) <= keep {
+		return nil
+	}
+	for _, b := range backups[keep:] {
+		if err := os.Remove(b.path); err != nil {
+			return fmt.Errorf("failed to remove old backup %s: %w", b.path, err)
+		}
+	}
+	return nil
+}
+
+// backupInfo describes one on-disk "<binary>.bak-<version>" backup file.
+type backupInfo struct {
+	path    string
+	version string
+	modTime time.Time
+}
+
+// listBackups returns finalBinaryPath's .bak-* backups, newest first.
+func listBackups(finalBinaryPath string) ([]backupInfo, error) {
+	dir := filepath.Dir(finalBinaryPath)
+	prefix := filepath.Base(finalBinaryPath) + ".bak-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	var backups []backupInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupInfo{
+			path:    filepath.Join(dir, entry.Name()),
+			version: strings.TrimPrefix(entry.Name(), prefix),
+			modTime: info.ModTime(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.After(backups[j].modTime) })
+	return backups, nil
+}
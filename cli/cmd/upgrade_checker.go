@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// upgradeStateEnvVar overrides the upgrade-state file path; used by tests
+// so they don't touch the real $HOME/.llamafarm directory.
+const upgradeStateEnvVar = "LF_UPGRADE_STATE_PATH"
+
+// upgradeCheckIntervalEnvVar overrides how often maybeCheckForUpgrade is
+// willing to hit the network when force isn't set.
+const upgradeCheckIntervalEnvVar = "LF_UPGRADE_CHECK_INTERVAL"
+
+// defaultUpgradeCheckInterval is used when upgradeCheckIntervalEnvVar isn't set.
+const defaultUpgradeCheckInterval = 6 * time.Hour
+
+// latestReleaseURL is GitHub's "latest release" API endpoint, which already
+// excludes drafts but not prereleases.
+const latestReleaseURL = "https://api.github.com/repos/llama-farm/llamafarm/releases/latest"
+
+// httpDoer is the subset of *http.Client maybeCheckForUpgrade needs,
+// substituted with a fake in tests.
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// httpClient performs the GitHub API request; overridden in tests.
+var httpClient httpDoer = &http.Client{Timeout: 10 * time.Second}
+
+// timeNow returns the current time; overridden in tests.
+var timeNow = time.Now
+
+// UpgradeInfo summarizes the result of a version check: what's currently
+// running versus what's available, and where to read about it.
+type UpgradeInfo struct {
+	CurrentVersion           string
+	CurrentVersionNormalized string
+	LatestVersion            string
+	LatestVersionNormalized  string
+	UpdateAvailable          bool
+	ReleaseURL               string
+	ReleaseNotes             string
+}
+
+// upgradeState is the JSON document persisted at the upgrade-state path
+// between runs, so the startup banner never has to hit the network to
+// decide whether to show itself.
+type upgradeState struct {
+	LastChecked time.Time `json:"last_checked_at"`
+	// FirstSeenAt records when LatestVersion first changed to its current
+	// value, so showUpgradeBanner can enforce a grace period before nagging
+	// about a release that just came out a minute ago.
+	FirstSeenAt   time.Time `json:"first_seen_at,omitempty"`
+	LatestVersion string    `json:"latest_version"`
+	ReleaseURL    string    `json:"release_url,omitempty"`
+	ReleaseNotes  string    `json:"release_notes,omitempty"`
+	NotifiedAt    time.Time `json:"notified_at,omitempty"`
+	SnoozedUntil  time.Time `json:"snoozed_until,omitempty"`
+}
+
+// githubRelease is the subset of GitHub's release API response this file needs.
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	Body        string    `json:"body"`
+}
+
+// getUpgradeStatePath resolves where the upgrade-state file lives, honoring
+// upgradeStateEnvVar before falling back to $HOME/.llamafarm/upgrade-state.json.
+func getUpgradeStatePath() (string, error) {
+	if v := strings.TrimSpace(os.Getenv(upgradeStateEnvVar)); v != "" {
+		return v, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".llamafarm", "upgrade-state.json"), nil
+}
+
+// loadUpgradeState reads and parses the upgrade-state file at path. A
+// missing or corrupt file isn't an error: it's treated as if `lf` had never
+// checked before, since a stale or unreadable cache shouldn't block the
+// next check.
+func loadUpgradeState(path string) (upgradeState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return upgradeState{}, nil
+		}
+		return upgradeState{}, fmt.Errorf("failed to read upgrade state: %w", err)
+	}
+	var state upgradeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return upgradeState{}, nil
+	}
+	return state, nil
+}
+
+// persistUpgradeState writes state to path as JSON, creating its parent
+// directory if needed.
+func persistUpgradeState(path string, state upgradeState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create upgrade state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode upgrade state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write upgrade state: %w", err)
+	}
+	return nil
+}
+
+// upgradeCheckInterval returns how often maybeCheckForUpgrade may hit the
+// network, honoring LF_UPGRADE_CHECK_INTERVAL.
+func upgradeCheckInterval() time.Duration {
+	if v := strings.TrimSpace(os.Getenv(upgradeCheckIntervalEnvVar)); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultUpgradeCheckInterval
+}
+
+// maybeCheckForUpgrade checks GitHub for the latest release, at most once
+// per upgradeCheckInterval unless force is set. It persists the outcome to
+// the upgrade-state file on every check it actually performs, so the next
+// call (and the startup banner) sees fresh data. It returns nil, nil
+// without error if no check was made because the interval hasn't elapsed.
+func maybeCheckForUpgrade(force bool) (*UpgradeInfo, error) {
+	statePath, err := getUpgradeStatePath()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := loadUpgradeState(statePath)
+	if err != nil {
+		return nil, err
+	}
+
+	now := timeNow()
+	if !force && !state.LastChecked.IsZero() && now.Sub(state.LastChecked) < upgradeCheckInterval() {
+		return nil, nil
+	}
+
+	release, err := fetchLatestRelease()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	if release.Draft || release.Prerelease {
+		return nil, fmt.Errorf("latest release %s is a draft or prerelease", release.TagName)
+	}
+
+	if release.TagName != state.LatestVersion {
+		state.FirstSeenAt = now
+	}
+	state.LastChecked = now
+	state.LatestVersion = release.TagName
+	state.ReleaseURL = release.HTMLURL
+	state.ReleaseNotes = release.Body
+	if err := persistUpgradeState(statePath, state); err != nil {
+		return nil, err
+	}
+
+	currentNormalized := normalizeVersion(Version)
+	info := &UpgradeInfo{
+		CurrentVersion:           Version,
+		CurrentVersionNormalized: currentNormalized,
+		LatestVersion:            release.TagName,
+		LatestVersionNormalized:  normalizeVersion(release.TagName),
+		UpdateAvailable:          compareVersions(release.TagName, currentNormalized) > 0,
+		ReleaseURL:               release.HTMLURL,
+		ReleaseNotes:             release.Body,
+	}
+	return info, nil
+}
+
+// fetchLatestRelease fetches and parses GitHub's latest-release API response.
+func fetchLatestRelease() (*githubRelease, error) {
+	req, err := http.NewRequest(http.MethodGet, latestReleaseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %s from GitHub releases API", resp.Status)
+	}
+
+	var release githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to parse release metadata: %w", err)
+	}
+	return &release, nil
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"time"
+
+	"llamafarm-cli/cmd/bsdiff"
+	"llamafarm-cli/cmd/tufclient"
+)
+
+func init() {
+	upgradeCmd.Flags().Bool("no-delta", false, "Always download the full binary instead of attempting a smaller delta update")
+	lfUpgradeCmd.Flags().Bool("no-delta", false, "Always download the full binary instead of attempting a smaller delta update")
+}
+
+// downloadAndVerifyBinaryViaDelta attempts to reconstruct targetVersion's
+// binary by downloading a bsdiff patch from currentVersion rather than the
+// full binary, and applying it against currentBinary. It returns the path
+// to the reconstructed binary and how many bytes smaller the patch was than
+// the full download. Any failure here - no patch published, a bad
+// signature, or a patch that doesn't apply cleanly - is returned as an
+// error, on which downloadAndVerifyBinary falls back to the full download.
+func downloadAndVerifyBinaryViaDelta(targetVersion, platform, currentBinary string, sig sigstoreFlags) (tempBinaryPath string, bytesSaved int64, err error) {
+	goos, goarch, err := splitPlatform(platform)
+	if err != nil {
+		return "", 0, err
+	}
+	currentVersion := normalizeVersion(Version)
+
+	client, err := tufclient.New(tufRepoURL())
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to initialize TUF client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	targets, err := client.Update(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to fetch TUF targets: %w", err)
+	}
+
+	fullTargetPath := tufclient.ResolveTargetPath(targetVersion, goos, goarch)
+	fullMeta, ok := targets.Targets[fullTargetPath]
+	if !ok {
+		return "", 0, fmt.Errorf("%s is not a known TUF target", fullTargetPath)
+	}
+	// fullMeta.Hashes["sha256"] is the hash of the gzip-compressed release
+	// asset, checked by client.DownloadTarget against the .gz download - it's
+	// never equal to a hash of the uncompressed binary bsdiff.Patch produces
+	// here. Verifying a delta-reconstructed binary requires the separate
+	// "sha256-uncompressed" hash the release process publishes alongside it.
+	wantSHA256, ok := fullMeta.Hashes["sha256-uncompressed"]
+	if !ok {
+		return "", 0, fmt.Errorf("%s has no sha256-uncompressed hash in TUF metadata; delta updates require this field to verify the reconstructed binary", fullTargetPath)
+	}
+
+	deltaTargetPath := tufclient.ResolveDeltaTargetPath(currentVersion, targetVersion, goos, goarch)
+
+	patchFile, err := os.CreateTemp("", "lf-upgrade-*.bsdiff")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	patchFile.Close()
+	defer os.Remove(patchFile.Name())
+
+	fmt.Fprintf(os.Stderr, "🔄 Looking for a delta update from %s to %s...\n", currentVersion, targetVersion)
+	if err := client.DownloadTarget(ctx, targets, deltaTargetPath, patchFile.Name()); err != nil {
+		return "", 0, fmt.Errorf("no delta update available: %w", err)
+	}
+
+	if sig.verifySignature {
+		if err := verifyBinarySignature(patchFile.Name(), tufRepoURL()+deltaTargetPath, sig); err != nil {
+			return "", 0, fmt.Errorf("delta patch signature verification failed: %w", err)
+		}
+	}
+
+	oldBinary, err := os.ReadFile(currentBinary)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read current binary: %w", err)
+	}
+	patch, err := os.ReadFile(patchFile.Name())
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read delta patch: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "🔄 Applying delta patch...\n")
+	newBinary, err := bsdiff.Patch(oldBinary, patch)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to apply delta patch: %w", err)
+	}
+
+	if gotSHA256 := fmt.Sprintf("%x", sha256.Sum256(newBinary)); gotSHA256 != wantSHA256 {
+		return "", 0, fmt.Errorf("reconstructed binary checksum mismatch: got %s, want %s", gotSHA256, wantSHA256)
+	}
+
+	tempBinary, err := os.CreateTemp("", "lf-upgrade-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer tempBinary.Close()
+	if _, err := tempBinary.Write(newBinary); err != nil {
+		return "", 0, fmt.Errorf("failed to write reconstructed binary: %w", err)
+	}
+	if err := os.Chmod(tempBinary.Name(), 0755); err != nil {
+		return "", 0, fmt.Errorf("failed to chmod reconstructed binary: %w", err)
+	}
+
+	patchInfo, err := os.Stat(patchFile.Name())
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to stat delta patch: %w", err)
+	}
+	bytesSaved = fullMeta.Length - patchInfo.Size()
+
+	return tempBinary.Name(), bytesSaved, nil
+}
+
+// deltaSavingsEstimate reports how many bytes smaller a delta update from
+// currentVersion to targetVersion would be than a full binary download, by
+// fetching TUF metadata and comparing the two targets' sizes in it, without
+// downloading or applying either one. ok is false if no delta is published
+// for this upgrade.
+func deltaSavingsEstimate(targetVersion, platform, currentVersion string) (bytesSaved int64, ok bool) {
+	goos, goarch, err := splitPlatform(platform)
+	if err != nil {
+		return 0, false
+	}
+
+	client, err := tufclient.New(tufRepoURL())
+	if err != nil {
+		return 0, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	targets, err := client.Update(ctx)
+	if err != nil {
+		return 0, false
+	}
+
+	fullMeta, ok := targets.Targets[tufclient.ResolveTargetPath(targetVersion, goos, goarch)]
+	if !ok {
+		return 0, false
+	}
+	deltaMeta, ok := targets.Targets[tufclient.ResolveDeltaTargetPath(currentVersion, targetVersion, goos, goarch)]
+	if !ok {
+		return 0, false
+	}
+
+	return fullMeta.Length - deltaMeta.Length, true
+}
+
+// formatBytes renders n as a short human-readable size, e.g. "850.0 KiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
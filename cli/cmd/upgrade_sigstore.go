@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"llamafarm-cli/cmd/sigstore"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultCosignIssuer is the OIDC issuer that signs official release
+// workflows: GitHub Actions' own token service.
+const defaultCosignIssuer = "https://token.actions.githubusercontent.com"
+
+// defaultCosignIdentity is the workflow identity that signs official
+// releases, matched against the signing certificate's SAN.
+const defaultCosignIdentity = "https://github.com/llama-farm/llamafarm/.github/workflows/release.yml@refs/heads/main"
+
+// rekorLogURLEnvVar overrides the default Rekor transparency log endpoint.
+const rekorLogURLEnvVar = "LF_REKOR_URL"
+
+// defaultRekorURL is used when --verify-signature is active but
+// LF_REKOR_URL isn't set.
+const defaultRekorURL = "https://rekor.llamafarm.dev"
+
+func init() {
+	upgradeCmd.Flags().Bool("verify-signature", true, "Require a verified cosign/sigstore signature before installing the downloaded binary")
+	upgradeCmd.Flags().String("cosign-identity", defaultCosignIdentity, "Expected signing workflow identity (certificate SAN)")
+	upgradeCmd.Flags().String("cosign-issuer", defaultCosignIssuer, "Expected OIDC issuer for the signing certificate")
+
+	lfUpgradeCmd.Flags().Bool("verify-signature", true, "Require a verified cosign/sigstore signature before installing the downloaded binary")
+	lfUpgradeCmd.Flags().String("cosign-identity", defaultCosignIdentity, "Expected signing workflow identity (certificate SAN)")
+	lfUpgradeCmd.Flags().String("cosign-issuer", defaultCosignIssuer, "Expected OIDC issuer for the signing certificate")
+}
+
+// sigstoreFlags contains the parsed cosign/sigstore verification flags.
+type sigstoreFlags struct {
+	verifySignature bool
+	cosignIdentity  string
+	cosignIssuer    string
+}
+
+// parseSigstoreFlags extracts the cosign/sigstore flags from cmd.
+func parseSigstoreFlags(cmd *cobra.Command) sigstoreFlags {
+	verifySignature, _ := cmd.Flags().GetBool("verify-signature")
+	cosignIdentity, _ := cmd.Flags().GetString("cosign-identity")
+	cosignIssuer, _ := cmd.Flags().GetString("cosign-issuer")
+
+	return sigstoreFlags{
+		verifySignature: verifySignature,
+		cosignIdentity:  cosignIdentity,
+		cosignIssuer:    cosignIssuer,
+	}
+}
+
+// verifyBinarySignature fetches the cosign/sigstore signing bundle published
+// alongside the release asset at assetURL and verifies binaryPath against
+// it: the signing certificate chains to the embedded Fulcio root, the
+// signature matches binaryPath, the signer identity matches flags, and
+// binaryPath's SHA-256 is recorded in the Rekor transparency log.
+func verifyBinarySignature(binaryPath, assetURL string, flags sigstoreFlags) error {
+	bundle, err := sigstore.FetchBundle(nil, assetURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signature bundle: %w", err)
+	}
+
+	identity, err := sigstore.VerifyArtifact(binaryPath, bundle, rekorLogURL(), flags.cosignIssuer, flags.cosignIdentity)
+	if err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "✅ Verified signature from %s (issuer %s)\n", identity.Subject, identity.Issuer)
+	return nil
+}
+
+// rekorLogURL returns the Rekor transparency log URL to check signatures
+// against, honoring LF_REKOR_URL if set.
+func rekorLogURL() string {
+	if v := strings.TrimSpace(os.Getenv(rekorLogURLEnvVar)); v != "" {
+		return v
+	}
+	return defaultRekorURL
+}
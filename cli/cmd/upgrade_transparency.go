@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"llamafarm-cli/cmd/transparency"
+)
+
+// transparencyLogURLEnvVar overrides the default (disabled) transparency log endpoint.
+const transparencyLogURLEnvVar = "LF_TRANSPARENCY_LOG_URL"
+
+// lastSTHFileName stores the most recently verified signed tree head so
+// successive upgrades can also check consistency, not just inclusion.
+const lastSTHFileName = "transparency-sth.json"
+
+// trustedSTH is the on-disk representation of the last signed tree head we verified.
+type trustedSTH struct {
+	TreeSize  int64  `json:"tree_size"`
+	RootHash  string `json:"root_hash"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func init() {
+	lfUpgradeCmd.Flags().Bool("verify-transparency", false, "Require a transparency-log inclusion proof before installing the downloaded binary")
+	upgradeCmd.Flags().Bool("verify-transparency", false, "Require a transparency-log inclusion proof before installing the downloaded binary")
+}
+
+// verifyTransparencyLog checks binaryPath's SHA-256 against the transparency
+// log at logURL, failing the upgrade if no inclusion proof can be obtained
+// and verified. On success it persists the signed tree head so the next
+// upgrade can additionally verify consistency against it.
+func verifyTransparencyLog(binaryPath, logURL string) error {
+	data, err := os.ReadFile(binaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to read downloaded binary for transparency verification: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	artifactSHA256 := hex.EncodeToString(sum[:])
+
+	proof, sth, err := transparency.FetchInclusionProof(nil, logURL, artifactSHA256)
+	if err != nil {
+		return fmt.Errorf("transparency log verification failed: %w", err)
+	}
+
+	leafHash := transparency.HashLeaf([]byte(artifactSHA256))
+	ok, err := transparency.VerifyInclusion(leafHash, *proof, *sth)
+	if err != nil {
+		return fmt.Errorf("transparency inclusion proof invalid: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("transparency log does not include artifact %s", artifactSHA256)
+	}
+
+	if err := checkAndPersistSTH(*sth, logURL); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkAndPersistSTH verifies the new signed tree head is consistent with
+// the last one we trusted (if any) by fetching and checking a consistency
+// proof between the two tree sizes, then stores the new one for next time.
+func checkAndPersistSTH(sth transparency.SignedTreeHead, logURL string) error {
+	path, err := lastSTHPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine transparency state path: %w", err)
+	}
+
+	if data, err := os.ReadFile(path); err == nil {
+		var last trustedSTH
+		if err := json.Unmarshal(data, &last); err == nil {
+			lastRoot, err := hex.DecodeString(last.RootHash)
+			if err != nil {
+				return fmt.Errorf("failed to decode last trusted tree head: %w", err)
+			}
+			oldSTH := transparency.SignedTreeHead{TreeSize: last.TreeSize, RootHash: lastRoot}
+			proof, err := transparency.FetchConsistencyProof(nil, logURL, oldSTH.TreeSize, sth.TreeSize)
+			if err != nil {
+				return fmt.Errorf("failed to fetch consistency proof: %w", err)
+			}
+			ok, err := transparency.VerifyConsistency(oldSTH, sth, proof)
+			if err != nil {
+				return fmt.Errorf("transparency log consistency check failed: %w", err)
+			}
+			if !ok {
+				return fmt.Errorf("transparency log tree head at size %d is not a consistent successor of the last trusted size %d; refusing to trust it", sth.TreeSize, oldSTH.TreeSize)
+			}
+		}
+	}
+
+	toStore := trustedSTH{TreeSize: sth.TreeSize, RootHash: hex.EncodeToString(sth.RootHash), Timestamp: sth.Timestamp}
+	encoded, err := json.Marshal(toStore)
+	if err != nil {
+		return fmt.Errorf("failed to encode signed tree head: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create transparency state directory: %w", err)
+	}
+	return os.WriteFile(path, encoded, 0o644)
+}
+
+// lastSTHPath places the cached signed tree head next to the upgrade state file.
+func lastSTHPath() (string, error) {
+	statePath, err := getUpgradeStatePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(statePath), lastSTHFileName), nil
+}
+
+// transparencyLogURLFromEnv returns the configured log URL, or "" if
+// LF_TRANSPARENCY_LOG_URL isn't set.
+func transparencyLogURLFromEnv() string {
+	return strings.TrimSpace(os.Getenv(transparencyLogURLEnvVar))
+}
+
+// transparencyLogURL returns the log URL to use, or "" if transparency
+// verification should be skipped. Verification is opt-in: it only runs when
+// explicitly requested via --verify-transparency or LF_TRANSPARENCY_LOG_URL.
+// When requested via the flag alone, defaultTransparencyLogURL is used.
+func transparencyLogURL(requested bool) string {
+	logURL := transparencyLogURLFromEnv()
+	if !requested && logURL == "" {
+		return ""
+	}
+	if logURL == "" {
+		logURL = defaultTransparencyLogURL
+	}
+	return logURL
+}
+
+// defaultTransparencyLogURL is used when verification is requested via
+// --verify-transparency but LF_TRANSPARENCY_LOG_URL isn't set.
+const defaultTransparencyLogURL = "https://transparency.llamafarm.dev/log"
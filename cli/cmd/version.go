@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"compress/gzip"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"llamafarm-cli/cmd/tufclient"
 
 	"github.com/spf13/cobra"
 )
@@ -35,18 +42,18 @@ Examples:
   lf version upgrade              # Upgrade to latest version
   lf version upgrade v1.2.3       # Upgrade to specific version
   lf version upgrade --dry-run    # Show what would be done
-  lf version upgrade --force      # Force upgrade even if same version`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		return performUpgrade(cmd, args)
-	},
+  lf version upgrade --force      # Force upgrade even if same version
+  lf version upgrade --check-only # Report whether an upgrade is available and exit`,
+	RunE: runUpgrade,
 }
 
 func init() {
 	// Add flags to upgrade command
 	upgradeCmd.Flags().Bool("dry-run", false, "Show upgrade plan without executing")
 	upgradeCmd.Flags().Bool("force", false, "Force upgrade even if same version")
-	upgradeCmd.Flags().Bool("no-verify", false, "Skip checksum verification (not recommended)")
+	upgradeCmd.Flags().Bool("no-verify", false, "Skip TUF and checksum verification entirely (DANGEROUS: disables all protection against a compromised release server)")
 	upgradeCmd.Flags().String("install-dir", "", "Override installation directory")
+	upgradeCmd.Flags().Bool("check-only", false, "Only report whether a newer release is available, without installing it")
 
 	versionCmd.AddCommand(upgradeCmd)
 	rootCmd.AddCommand(versionCmd)
@@ -54,10 +61,12 @@ func init() {
 
 // upgradeFlags contains parsed command-line flags for the upgrade command
 type upgradeFlags struct {
-	dryRun     bool
-	force      bool
-	noVerify   bool
-	installDir string
+	dryRun             bool
+	force              bool
+	noVerify           bool
+	noDelta            bool
+	installDir         string
+	verifyTransparency bool
 }
 
 // parseUpgradeFlags extracts and returns the upgrade command flags
@@ -65,18 +74,26 @@ func parseUpgradeFlags(cmd *cobra.Command) upgradeFlags {
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	force, _ := cmd.Flags().GetBool("force")
 	noVerify, _ := cmd.Flags().GetBool("no-verify")
+	noDelta, _ := cmd.Flags().GetBool("no-delta")
 	installDir, _ := cmd.Flags().GetString("install-dir")
+	verifyTransparency, _ := cmd.Flags().GetBool("verify-transparency")
 
 	return upgradeFlags{
-		dryRun:     dryRun,
-		force:      force,
-		noVerify:   noVerify,
-		installDir: installDir,
+		dryRun:             dryRun,
+		force:              force,
+		noVerify:           noVerify,
+		noDelta:            noDelta,
+		installDir:         installDir,
+		verifyTransparency: verifyTransparency,
 	}
 }
 
-// determineTargetVersion resolves the target version from args or fetches the latest
-func determineTargetVersion(args []string) (string, *UpgradeInfo, error) {
+// determineTargetVersion resolves the target version from args or fetches
+// the latest. When noVerify is false, the latest version is looked up
+// against the TUF targets list rather than the GitHub API, so the version
+// the CLI decides to install is backed by the same trusted metadata it will
+// verify the download against.
+func determineTargetVersion(args []string, noVerify bool) (string, *UpgradeInfo, error) {
 	var targetVersion string
 	var info *UpgradeInfo
 
@@ -91,6 +108,12 @@ func determineTargetVersion(args []string) (string, *UpgradeInfo, error) {
 			LatestVersionNormalized: targetVersion,
 			UpdateAvailable:         true,
 		}
+	} else if !noVerify {
+		var err error
+		targetVersion, info, err = determineTargetVersionViaTUF()
+		if err != nil {
+			return "", nil, err
+		}
 	} else {
 		// Get latest version
 		var err error
@@ -107,13 +130,111 @@ func determineTargetVersion(args []string) (string, *UpgradeInfo, error) {
 	return targetVersion, info, nil
 }
 
+// determineTargetVersionViaTUF consults the TUF targets list for the latest
+// available release, rather than the GitHub API.
+func determineTargetVersionViaTUF() (string, *UpgradeInfo, error) {
+	client, err := tufclient.New(tufRepoURL())
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to initialize TUF client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	targets, err := client.Update(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch TUF targets: %w", err)
+	}
+
+	latest, err := latestVersionFromTargets(targets)
+	if err != nil {
+		return "", nil, err
+	}
+
+	currentNormalized := normalizeVersion(Version)
+	info := &UpgradeInfo{
+		CurrentVersion:           Version,
+		CurrentVersionNormalized: currentNormalized,
+		LatestVersion:            latest,
+		LatestVersionNormalized:  latest,
+		UpdateAvailable:          latest != currentNormalized,
+	}
+	return latest, info, nil
+}
+
+// latestVersionFromTargets extracts the distinct release versions named by
+// TUF target paths (e.g. "/lf-v1.2.3-linux-amd64.gz") and returns the
+// highest one.
+func latestVersionFromTargets(targets *tufclient.TargetsSigned) (string, error) {
+	var latest string
+	for path := range targets.Targets {
+		name := strings.TrimPrefix(path, "/")
+		name = strings.TrimSuffix(name, ".gz")
+		parts := strings.Split(name, "-")
+		if len(parts) < 4 || parts[0] != "lf" {
+			continue
+		}
+		version := normalizeVersion(parts[1])
+		if latest == "" || compareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no lf release targets found in TUF metadata")
+	}
+	return latest, nil
+}
+
+// compareVersions compares two "vMAJOR.MINOR.PATCH"-style versions,
+// returning -1, 0, or 1. Non-numeric components compare as 0 (equal).
+func compareVersions(a, b string) int {
+	aParts := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bParts := strings.Split(strings.TrimPrefix(b, "v"), ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aNum, bNum int
+		if i < len(aParts) {
+			aNum, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bNum, _ = strconv.Atoi(bParts[i])
+		}
+		if aNum != bNum {
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// tufRepoURL returns the TUF repository base URL, overridable via
+// LF_TUF_URL for testing against a non-production repository.
+func tufRepoURL() string {
+	if v := strings.TrimSpace(os.Getenv("LF_TUF_URL")); v != "" {
+		return v
+	}
+	return defaultTUFRepoURL
+}
+
+// defaultTUFRepoURL is the production TUF repository serving signed release
+// metadata and binaries.
+const defaultTUFRepoURL = "https://tuf.llamafarm.dev"
+
 // showUpgradePlan displays the upgrade plan to the user
-func showUpgradePlan(info *UpgradeInfo, targetVersion, finalInstallDir string, strategy UpgradeStrategy, canUpgradeInPlace bool, installDir string) {
+func showUpgradePlan(info *UpgradeInfo, targetVersion, finalInstallDir string, strategy UpgradeStrategy, canUpgradeInPlace bool, installDir, platform string, noDelta bool) {
 	fmt.Printf("📋 Upgrade Plan:\n")
 	fmt.Printf("   Current version: %s\n", info.CurrentVersion)
 	fmt.Printf("   Target version:  %s\n", targetVersion)
 	fmt.Printf("   Install location: %s\n", finalInstallDir)
-	fmt.Printf("   Platform: %s\n", detectPlatform())
+	fmt.Printf("   Platform: %s\n", platform)
+
+	if !noDelta {
+		if saved, ok := deltaSavingsEstimate(targetVersion, platform, info.CurrentVersionNormalized); ok && saved > 0 {
+			fmt.Printf("   💾 Delta update available: ~%s smaller than a full download\n", formatBytes(saved))
+		}
+	}
 
 	requiresElevation := strategy.RequiresElevation(finalInstallDir)
 	if requiresElevation {
@@ -153,28 +274,134 @@ func checkPermissions(canUpgradeInPlace bool, installDir, finalInstallDir string
 	return fmt.Errorf("insufficient permissions for upgrade")
 }
 
-// downloadAndVerifyBinary downloads the binary and optionally verifies its checksum
-func downloadAndVerifyBinary(targetVersion, platform string, noVerify bool) (string, error) {
-	fmt.Fprintf(os.Stderr, "🔄 Downloading binary...\n")
-	tempBinary, err := downloadBinary(targetVersion, platform)
+// downloadAndVerifyBinary downloads the binary for targetVersion/platform. By
+// default it is fetched and verified through TUF, so the binary's hash is
+// checked against signed, threshold-signed metadata rather than a single
+// checksum file served alongside it, and (when sig.verifySignature is set)
+// its cosign/sigstore signature is checked too. Passing noVerify skips TUF
+// and signature verification entirely and falls back to the legacy
+// unauthenticated download, with no delta attempt. Otherwise, unless
+// noDelta is set, a bsdiff delta from the
+// currently running binary is tried first and falls back transparently to
+// the full download on any failure.
+func downloadAndVerifyBinary(targetVersion, platform string, noVerify, noDelta bool, currentBinary string, sig sigstoreFlags) (string, error) {
+	if noVerify {
+		fmt.Fprintf(os.Stderr, "⚠️  --no-verify set: downloading %s without TUF, signature, or checksum verification. "+
+			"This binary's authenticity and integrity will NOT be checked before install.\n", targetVersion)
+		tempBinary, err := downloadBinary(targetVersion, platform)
+		if err != nil {
+			return "", fmt.Errorf("failed to download binary: %w", err)
+		}
+		return tempBinary, nil
+	}
+
+	if !noDelta {
+		tempBinary, bytesSaved, err := downloadAndVerifyBinaryViaDelta(targetVersion, platform, currentBinary, sig)
+		if err == nil {
+			fmt.Fprintf(os.Stderr, "✅ Delta update applied (%s smaller than a full download)\n", formatBytes(bytesSaved))
+			return tempBinary, nil
+		}
+		fmt.Fprintf(os.Stderr, "ℹ️  %v; falling back to full download\n", err)
+	}
+
+	return downloadAndVerifyBinaryViaTUF(targetVersion, platform, sig)
+}
+
+// downloadAndVerifyBinaryViaTUF downloads the release binary named by
+// targetVersion/platform, verifying it against TUF targets metadata and,
+// when sig.verifySignature is set, its cosign/sigstore signature.
+func downloadAndVerifyBinaryViaTUF(targetVersion, platform string, sig sigstoreFlags) (string, error) {
+	goos, goarch, err := splitPlatform(platform)
 	if err != nil {
-		return "", fmt.Errorf("failed to download binary: %w", err)
+		return "", err
 	}
 
-	if !noVerify {
-		fmt.Fprintf(os.Stderr, "🔄 Verifying checksum...\n")
-		err = verifyChecksum(tempBinary, targetVersion, platform)
-		if err != nil {
-			cleanupTempFiles([]string{tempBinary})
-			return "", fmt.Errorf("checksum verification failed: %w", err)
+	fmt.Fprintf(os.Stderr, "🔄 Fetching TUF metadata...\n")
+	client, err := tufclient.New(tufRepoURL())
+	if err != nil {
+		return "", fmt.Errorf("failed to initialize TUF client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	targets, err := client.Update(ctx)
+	if err != nil {
+		return "", fmt.Errorf("TUF metadata verification failed: %w", err)
+	}
+
+	targetPath := tufclient.ResolveTargetPath(targetVersion, goos, goarch)
+
+	compressedFile, err := os.CreateTemp("", "lf-upgrade-*.gz")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	compressedFile.Close()
+	defer os.Remove(compressedFile.Name())
+
+	fmt.Fprintf(os.Stderr, "🔄 Downloading and verifying binary via TUF...\n")
+	if err := client.DownloadTarget(ctx, targets, targetPath, compressedFile.Name()); err != nil {
+		return "", fmt.Errorf("TUF download verification failed: %w", err)
+	}
+
+	if sig.verifySignature {
+		fmt.Fprintf(os.Stderr, "🔄 Verifying cosign/sigstore signature...\n")
+		if err := verifyBinarySignature(compressedFile.Name(), tufRepoURL()+targetPath, sig); err != nil {
+			return "", err
 		}
-	} else {
-		fmt.Fprintf(os.Stderr, "⚠️  Skipping checksum verification\n")
+	}
+
+	tempBinary, err := decompressGzip(compressedFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress downloaded binary: %w", err)
 	}
 
 	return tempBinary, nil
 }
 
+// decompressGzip gunzips srcPath to a new temp file and returns its path.
+func decompressGzip(srcPath string) (string, error) {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	gzReader, err := gzip.NewReader(src)
+	if err != nil {
+		return "", fmt.Errorf("not a valid gzip stream: %w", err)
+	}
+	defer gzReader.Close()
+
+	dest, err := os.CreateTemp("", "lf-upgrade-*")
+	if err != nil {
+		return "", err
+	}
+	defer dest.Close()
+
+	if _, err := io.Copy(dest, gzReader); err != nil {
+		os.Remove(dest.Name())
+		return "", err
+	}
+
+	if err := os.Chmod(dest.Name(), 0755); err != nil {
+		os.Remove(dest.Name())
+		return "", err
+	}
+
+	return dest.Name(), nil
+}
+
+// splitPlatform splits a "<goos>-<goarch>" platform string, as returned by
+// detectPlatform, into its components.
+func splitPlatform(platform string) (goos, goarch string, err error) {
+	parts := strings.SplitN(platform, "-", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid platform %q, expected \"<os>-<arch>\"", platform)
+	}
+	return parts[0], parts[1], nil
+}
+
 // determineFinalBinaryPath resolves the final installation path for the binary
 func determineFinalBinaryPath(installDir, currentBinary, platform string) (string, error) {
 	if installDir != "" {
@@ -210,7 +437,7 @@ func performUpgrade(cmd *cobra.Command, args []string) error {
 	fmt.Fprintf(os.Stderr, "🔍 Current binary: %s\n", currentBinary)
 
 	// Determine target version
-	targetVersion, info, err := determineTargetVersion(args)
+	targetVersion, info, err := determineTargetVersion(args, flags.noVerify)
 	if err != nil {
 		return err
 	}
@@ -235,8 +462,10 @@ func performUpgrade(cmd *cobra.Command, args []string) error {
 	// Check if we can upgrade to the current location
 	canUpgradeInPlace := strategy.CanUpgrade(currentBinary) && canWriteToLocation(currentBinary)
 
+	platform := detectPlatform()
+
 	// Show upgrade plan
-	showUpgradePlan(info, targetVersion, finalInstallDir, strategy, canUpgradeInPlace, flags.installDir)
+	showUpgradePlan(info, targetVersion, finalInstallDir, strategy, canUpgradeInPlace, flags.installDir, platform, flags.noDelta)
 
 	if flags.dryRun {
 		fmt.Printf("\n🔍 Dry run mode - no changes will be made\n")
@@ -251,34 +480,36 @@ func performUpgrade(cmd *cobra.Command, args []string) error {
 	// Confirm upgrade
 	fmt.Printf("\n🚀 Starting upgrade to %s...\n", targetVersion)
 
-	platform := detectPlatform()
-
 	// Download and verify binary
-	tempBinary, err := downloadAndVerifyBinary(targetVersion, platform, flags.noVerify)
+	sigFlags := parseSigstoreFlags(cmd)
+	tempBinary, err := downloadAndVerifyBinary(targetVersion, platform, flags.noVerify, flags.noDelta, currentBinary, sigFlags)
 	if err != nil {
 		return err
 	}
 	defer cleanupTempFiles([]string{tempBinary})
 
+	if logURL := transparencyLogURL(flags.verifyTransparency); logURL != "" {
+		fmt.Fprintf(os.Stderr, "🔄 Verifying transparency log inclusion...\n")
+		if err := verifyTransparencyLog(tempBinary, logURL); err != nil {
+			return fmt.Errorf("transparency verification failed: %w", err)
+		}
+	}
+
 	// Determine final binary path
 	finalBinaryPath, err := determineFinalBinaryPath(flags.installDir, currentBinary, platform)
 	if err != nil {
 		return err
 	}
 
-	// Perform upgrade
+	// Perform upgrade: swap the new binary in atomically, keeping the
+	// previous one as a backup, and roll back automatically if the new
+	// binary fails its post-install self-check.
 	fmt.Fprintf(os.Stderr, "🔄 Installing new version...\n")
-	err = strategy.PerformUpgrade(finalBinaryPath, tempBinary)
-	if err != nil {
+	keepBackups, _ := cmd.Flags().GetInt("keep-backups")
+	if err := performAtomicUpgrade(finalBinaryPath, tempBinary, normalizeVersion(Version), keepBackups); err != nil {
 		return fmt.Errorf("upgrade failed: %w", err)
 	}
 
-	// Verify installation
-	fmt.Fprintf(os.Stderr, "🔄 Verifying installation...\n")
-	if err := validateBinaryPath(finalBinaryPath); err != nil {
-		return fmt.Errorf("installation verification failed: %w", err)
-	}
-
 	fmt.Fprintf(os.Stderr, "✅ Upgrade completed successfully!\n")
 	fmt.Printf("\nRun 'lf version' to confirm the new version.\n")
 
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// notifyGraceEnvVar overrides how long an upgrade must have been known
+// about before the startup banner starts showing it.
+const notifyGraceEnvVar = "LF_UPGRADE_NOTIFY_GRACE"
+
+// defaultNotifyGrace is used when notifyGraceEnvVar isn't set.
+const defaultNotifyGrace = 24 * time.Hour
+
+var versionCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check whether a newer LlamaFarm CLI release is available",
+	Long: `Check whether a newer LlamaFarm CLI release is available, without installing it.
+
+Examples:
+  lf version check            # Check now and print the result
+  lf version check --json     # Machine-readable output, for scripting
+  lf version check --watch    # Run the background check loop in the foreground`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		watch, _ := cmd.Flags().GetBool("watch")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		if watch {
+			return runVersionCheckWatch(asJSON)
+		}
+		return runVersionCheck(asJSON)
+	},
+}
+
+var versionSnoozeCmd = &cobra.Command{
+	Use:   "snooze <duration>",
+	Short: "Silence the upgrade-available banner for a duration",
+	Long: `Silence the upgrade-available banner shown at startup for the given duration.
+
+Examples:
+  lf version snooze 24h
+  lf version snooze 168h   # one week`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runVersionSnooze(args[0])
+	},
+}
+
+func init() {
+	versionCheckCmd.Flags().Bool("watch", false, "Keep checking in the foreground every LF_UPGRADE_CHECK_INTERVAL instead of exiting after one check")
+	versionCheckCmd.Flags().Bool("json", false, "Print the check result as JSON")
+	versionCmd.AddCommand(versionCheckCmd)
+	versionCmd.AddCommand(versionSnoozeCmd)
+
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		showUpgradeBanner()
+		spawnUpgradeCheckDaemon()
+	}
+}
+
+// runVersionCheck performs a single foreground check and reports the result.
+func runVersionCheck(asJSON bool) error {
+	info, err := maybeCheckForUpgrade(true)
+	if err != nil {
+		return err
+	}
+	return reportVersionCheck(info, asJSON)
+}
+
+// runVersionCheckWatch repeats the check loop in the foreground, sleeping
+// upgradeCheckInterval between checks, until interrupted. It's meant for
+// users who want to watch the same staged-notification logic rootCmd's
+// background daemon uses, rather than wait for it to fire silently.
+func runVersionCheckWatch(asJSON bool) error {
+	for {
+		info, err := maybeCheckForUpgrade(false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "check failed: %v\n", err)
+		} else if err := reportVersionCheck(info, asJSON); err != nil {
+			return err
+		}
+		time.Sleep(upgradeCheckInterval())
+	}
+}
+
+// reportVersionCheck prints a single maybeCheckForUpgrade result, either as
+// JSON (for scripting) or as the usual human-readable summary. info is nil
+// when the check interval hadn't elapsed, which only happens via the
+// --watch loop's unforced calls.
+func reportVersionCheck(info *UpgradeInfo, asJSON bool) error {
+	if asJSON {
+		data, err := json.Marshal(info)
+		if err != nil {
+			return fmt.Errorf("failed to encode check result: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if info == nil || !info.UpdateAvailable {
+		fmt.Printf("✅ Already running the latest version\n")
+		return nil
+	}
+
+	fmt.Printf("📦 Update available: %s -> %s\n", info.CurrentVersion, info.LatestVersion)
+	if info.ReleaseURL != "" {
+		fmt.Printf("   Release notes: %s\n", info.ReleaseURL)
+	}
+	return nil
+}
+
+// runVersionSnooze records duration as a snoozed_until timestamp in the
+// upgrade-state file, so showUpgradeBanner stays quiet until then.
+func runVersionSnooze(duration string) error {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", duration, err)
+	}
+
+	statePath, err := getUpgradeStatePath()
+	if err != nil {
+		return err
+	}
+	state, err := loadUpgradeState(statePath)
+	if err != nil {
+		return err
+	}
+
+	state.SnoozedUntil = timeNow().Add(d)
+	if err := persistUpgradeState(statePath, state); err != nil {
+		return err
+	}
+
+	fmt.Printf("🔕 Upgrade notifications snoozed until %s\n", state.SnoozedUntil.Format(time.RFC3339))
+	return nil
+}
+
+// spawnUpgradeCheckDaemon kicks off a background upgrade check in a
+// detached goroutine; maybeCheckForUpgrade's own interval gate makes this a
+// fast no-op when the last check is still fresh. It never blocks rootCmd's
+// invocation on the network, matching the always-warn-but-never-block UX
+// other self-updaters use.
+func spawnUpgradeCheckDaemon() {
+	go func() {
+		_, _ = maybeCheckForUpgrade(false)
+	}()
+}
+
+// showUpgradeBanner prints a one-line stderr notice if the last known
+// upgrade check found a newer release that has been available for more
+// than the notify grace period and the user hasn't snoozed it. It only
+// reads the upgrade-state file, so it never blocks on the network.
+func showUpgradeBanner() {
+	statePath, err := getUpgradeStatePath()
+	if err != nil {
+		return
+	}
+	state, err := loadUpgradeState(statePath)
+	if err != nil || state.LatestVersion == "" {
+		return
+	}
+
+	now := timeNow()
+	if now.Before(state.SnoozedUntil) {
+		return
+	}
+	if state.FirstSeenAt.IsZero() || now.Sub(state.FirstSeenAt) < notifyGrace() {
+		return
+	}
+	if compareVersions(state.LatestVersion, normalizeVersion(Version)) <= 0 {
+		return
+	}
+
+	releaseURL := ""
+	if state.ReleaseURL != "" {
+		releaseURL = " " + state.ReleaseURL
+	}
+	fmt.Fprintf(os.Stderr, "📦 A newer LlamaFarm CLI release is available: %s%s (run 'lf upgrade', or 'lf version snooze <duration>' to silence this)\n", state.LatestVersion, releaseURL)
+
+	state.NotifiedAt = now
+	_ = persistUpgradeState(statePath, state)
+}
+
+// notifyGrace returns how long an available upgrade must have been known
+// about before showUpgradeBanner starts showing it, honoring
+// LF_UPGRADE_NOTIFY_GRACE.
+func notifyGrace() time.Duration {
+	if v := strings.TrimSpace(os.Getenv(notifyGraceEnvVar)); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultNotifyGrace
+}
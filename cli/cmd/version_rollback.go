@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var versionRollbackCmd = &cobra.Command{
+	Use:   "rollback [version]",
+	Short: "Restore a previous CLI binary from an upgrade backup",
+	Long: `Restore a binary previously backed up by 'lf version upgrade'.
+
+With no argument, the most recently created backup is restored. Pass a
+version to restore a specific backup instead.
+
+Examples:
+  lf version rollback            # Restore the most recent backup
+  lf version rollback v1.2.3     # Restore a specific backed-up version`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runVersionRollback(args)
+	},
+}
+
+func init() {
+	versionCmd.AddCommand(versionRollbackCmd)
+}
+
+func runVersionRollback(args []string) error {
+	currentBinary, err := getCurrentBinaryPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine current binary location: %w", err)
+	}
+
+	backups, err := listBackups(currentBinary)
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		return fmt.Errorf("no upgrade backups found next to %s", currentBinary)
+	}
+
+	chosen := &backups[0]
+	if len(args) > 0 {
+		want := normalizeVersion(args[0])
+		chosen = nil
+		for i := range backups {
+			if normalizeVersion(backups[i].version) == want {
+				chosen = &backups[i]
+				break
+			}
+		}
+		if chosen == nil {
+			return fmt.Errorf("no backup found for version %s", args[0])
+		}
+	}
+
+	fmt.Printf("🔄 Rolling back to %s...\n", chosen.version)
+
+	superseded := backupPath(currentBinary, "pre-rollback-"+time.Now().UTC().Format("20060102150405"))
+	if err := os.Rename(currentBinary, superseded); err != nil {
+		return fmt.Errorf("failed to set aside current binary: %w", err)
+	}
+
+	if err := os.Rename(chosen.path, currentBinary); err != nil {
+		os.Rename(superseded, currentBinary)
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	if err := validateBinaryPath(currentBinary); err != nil {
+		os.Rename(currentBinary, chosen.path)
+		os.Rename(superseded, currentBinary)
+		return fmt.Errorf("restored binary failed validation, rolled back: %w", err)
+	}
+
+	os.Remove(superseded)
+	fmt.Printf("✅ Restored %s\n", chosen.version)
+	return nil
+}
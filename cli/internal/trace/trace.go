@@ -0,0 +1,83 @@
+// Package trace implements syncthing-style opt-in debug tracing: named
+// categories are enabled individually via the LF_TRACE environment variable
+// so verbose subsystems don't flood output unless a user actually asks for
+// them.
+package trace
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Known trace categories. All enables every category at once.
+const (
+	Config   = "config"
+	Watcher  = "watcher"
+	Docker   = "docker"
+	Designer = "designer"
+	TUI      = "tui"
+	Chat     = "chat"
+	Ready    = "ready"
+	All      = "all"
+)
+
+var (
+	mu       sync.RWMutex
+	enabled  map[string]bool
+	loadOnce sync.Once
+)
+
+// load parses LF_TRACE once, the first time it's needed.
+func load() {
+	loadOnce.Do(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		enabled = parse(os.Getenv("LF_TRACE"))
+	})
+}
+
+func parse(value string) map[string]bool {
+	set := make(map[string]bool)
+	for _, cat := range strings.Split(value, ",") {
+		cat = strings.ToLower(strings.TrimSpace(cat))
+		if cat != "" {
+			set[cat] = true
+		}
+	}
+	return set
+}
+
+// Enabled reports whether category is active, either directly or via "all".
+func Enabled(category string) bool {
+	load()
+	mu.RLock()
+	defer mu.RUnlock()
+	return enabled[All] || enabled[category]
+}
+
+// Log writes a trace line for category to stderr if it's enabled, prefixed
+// with [trace:category] so multiple active categories stay easy to tell
+// apart in interleaved output.
+func Log(category, format string, args ...interface{}) {
+	if !Enabled(category) {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "[trace:%s] %s\n", category, fmt.Sprintf(format, args...))
+}
+
+// Active returns the sorted list of categories LF_TRACE enabled, for startup
+// banners.
+func Active() []string {
+	load()
+	mu.RLock()
+	defer mu.RUnlock()
+	cats := make([]string, 0, len(enabled))
+	for c := range enabled {
+		cats = append(cats, c)
+	}
+	sort.Strings(cats)
+	return cats
+}